@@ -0,0 +1,43 @@
+// Command slog 提供 darkit/slog 的运维命令行工具。
+//
+// 用法:
+//
+//	slog tail [flags]    连接 TailServer 并实时打印日志
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	switch args[0] {
+	case "tail":
+		return runTail(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "slog: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return 2
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: slog <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  tail    stream live records from a slog TailServer")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'slog <command> -h' for command flags.")
+}
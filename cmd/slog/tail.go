@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/darkit/slog"
+)
+
+// attrFlags 收集可重复的 -attr key=value 参数。
+type attrFlags map[string]string
+
+func (a attrFlags) String() string {
+	pairs := make([]string, 0, len(a))
+	for k, v := range a {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (a attrFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	a[key] = val
+	return nil
+}
+
+func runTail(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	network := fs.String("network", "tcp", "network of the tail server: tcp or unix")
+	addr := fs.String("addr", "127.0.0.1:7070", "address of the tail server (socket path for unix)")
+	level := fs.String("level", "", "minimum level to print: trace, debug, info, warn, error, fatal")
+	contains := fs.String("grep", "", "only print records whose message contains this text")
+	pattern := fs.String("match", "", "only print records whose message matches this regexp")
+	noColor := fs.Bool("no-color", false, "disable colored output")
+	rendered := fs.Bool("rendered", false, "print the server-side rendered line instead of re-rendering")
+	raw := fs.Bool("raw", false, "print raw NDJSON events")
+	timeout := fs.Duration("dial-timeout", 3*time.Second, "dial timeout")
+	useTLS := fs.Bool("tls", false, "connect to the tail server over TLS")
+	caFile := fs.String("tls-ca", "", "PEM CA file used to verify the server (default: system roots)")
+	certFile := fs.String("tls-cert", "", "PEM client certificate for servers that require one")
+	keyFile := fs.String("tls-key", "", "PEM client key matching -tls-cert")
+	serverName := fs.String("tls-server-name", "", "server name to verify (default: host of -addr)")
+	attrs := attrFlags{}
+	fs.Var(attrs, "attr", "only print records with attr key=value (repeatable, groups joined by '.')")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	filter := slog.TailFilter{Contains: *contains, Attrs: attrs}
+	if *level != "" {
+		lvl, err := parseLevel(*level)
+		if err != nil {
+			fmt.Fprintf(stderr, "slog tail: %v\n", err)
+			return 2
+		}
+		filter.MinLevel = &lvl
+	}
+	if *pattern != "" {
+		re, err := regexp.Compile(*pattern)
+		if err != nil {
+			fmt.Fprintf(stderr, "slog tail: invalid -match: %v\n", err)
+			return 2
+		}
+		filter.Pattern = re
+	}
+
+	var conn net.Conn
+	var err error
+	if *useTLS {
+		var cfg *tls.Config
+		cfg, err = tailTLSConfig(*caFile, *certFile, *keyFile, *serverName)
+		if err == nil {
+			conn, err = tls.DialWithDialer(&net.Dialer{Timeout: *timeout}, *network, *addr, cfg)
+		}
+	} else {
+		conn, err = net.DialTimeout(*network, *addr, *timeout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "slog tail: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	printer := newTailPrinter(stdout, *noColor, *rendered, *raw)
+	err = slog.ReadTailEvents(conn, func(event slog.TailEvent) error {
+		if !filter.Match(event) {
+			return nil
+		}
+		return printer.print(event)
+	})
+	if err != nil && ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+		fmt.Fprintf(stderr, "slog tail: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// tailTLSConfig 按命令行参数构建客户端 TLS 配置。
+func tailTLSConfig(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: serverName}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("-tls-cert and -tls-key must be set together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+type tailPrinter struct {
+	w        io.Writer
+	console  slog.Handler
+	rendered bool
+	raw      bool
+}
+
+func newTailPrinter(w io.Writer, noColor, rendered, raw bool) *tailPrinter {
+	return &tailPrinter{
+		w:        w,
+		console:  slog.NewConsoleHandler(w, noColor, slog.NewOptions(&slog.HandlerOptions{Level: slog.LevelTrace}), slog.WithColorMode(slog.ColorAuto)),
+		rendered: rendered,
+		raw:      raw,
+	}
+}
+
+func (p *tailPrinter) print(event slog.TailEvent) error {
+	switch {
+	case p.raw:
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.w, string(line))
+		return err
+	case p.rendered && event.Rendered != "":
+		_, err := fmt.Fprintln(p.w, event.Rendered)
+		return err
+	default:
+		return p.console.Handle(context.Background(), event.Record())
+	}
+}
+
+func parseLevel(name string) (slog.Level, error) {
+	level, err := slog.ParseLevel(name)
+	if err != nil {
+		return 0, fmt.Errorf("invalid level %q", name)
+	}
+	return level, nil
+}
@@ -0,0 +1,176 @@
+package slog
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PrettyOptions 配置控制台的多行输出：首行为时间、级别与消息，属性逐行缩进列出，
+// 分组以树形展开，多行字符串与调用栈整体缩进。适合开发环境阅读。
+type PrettyOptions struct {
+	AlignKeys bool // 同一层级的键按最长键名对齐
+	Indent    int  // 属性缩进空格数，<=0 时为 2
+}
+
+// WithPretty 启用多行输出。动态渲染（Progress 等）仍保持单行。
+func WithPretty(opts PrettyOptions) ConsoleOption {
+	return func(c *consoleConfig) {
+		if opts.Indent <= 0 {
+			opts.Indent = 2
+		}
+		c.pretty = &opts
+	}
+}
+
+// prettyAttr 记录 WithAttrs 添加属性时所在的分组路径。
+type prettyAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// prettyNode 是属性树的节点，group 为 true 时 children 有效。
+type prettyNode struct {
+	key      string
+	val      slog.Value
+	group    bool
+	children []*prettyNode
+}
+
+func (n *prettyNode) child(key string) *prettyNode {
+	for _, c := range n.children {
+		if c.group && c.key == key {
+			return c
+		}
+	}
+	c := &prettyNode{key: key, group: true}
+	n.children = append(n.children, c)
+	return c
+}
+
+// appendPrettyAttrs 把 WithAttrs 属性与记录属性合并为树后逐行写入，最后输出调用栈。
+func (h *handler) appendPrettyAttrs(sb *buffer, r slog.Record) {
+	root := &prettyNode{group: true}
+	for _, pa := range h.prettyAttrs {
+		h.addPrettyAttr(root, pa.groups, pa.attr)
+	}
+	groups := h.groupPrefix.values()
+	var stack Stacktrace
+	r.Attrs(func(a slog.Attr) bool {
+		if rest, st, ok := splitStacktrace(a); ok {
+			stack = st
+			if rest.Key == "" {
+				return true
+			}
+			a = rest
+		}
+		h.addPrettyAttr(root, groups, a)
+		return true
+	})
+	h.appendPrettyNodes(sb, root.children, strings.Repeat(" ", h.pretty.Indent), false)
+	h.appendStacktrace(sb, stack)
+}
+
+func (h *handler) addPrettyAttr(root *prettyNode, groups []string, a slog.Attr) {
+	if a.Value.Kind() == slog.KindLogValuer {
+		a.Value = a.Value.Resolve()
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, child := range attrs {
+			h.addPrettyAttr(root, groups, child)
+		}
+		return
+	}
+	if h.replaceAttr != nil {
+		a = h.replaceAttr(groups, a)
+	}
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	node := root
+	for _, g := range groups {
+		node = node.child(g)
+	}
+	node.children = append(node.children, &prettyNode{key: a.Key, val: a.Value})
+}
+
+// appendPrettyNodes 写入同一层级的节点；tree 为 true 时使用树形连接符。
+func (h *handler) appendPrettyNodes(sb *buffer, nodes []*prettyNode, prefix string, tree bool) {
+	width := 0
+	if h.pretty.AlignKeys {
+		for _, n := range nodes {
+			if !n.group {
+				width = max(width, utf8.RuneCountInString(prettyKey(n.key)))
+			}
+		}
+	}
+	for i, n := range nodes {
+		branch, cont := "", ""
+		if tree {
+			branch, cont = "├─ ", "│  "
+			if i == len(nodes)-1 {
+				branch, cont = "└─ ", "   "
+			}
+		}
+		sb.AppendString(prefix)
+		sb.AppendString(branch)
+		key := prettyKey(n.key)
+		h.appendStyled(sb, h.styles().Key, key)
+		if n.group {
+			sb.AppendByte('\n')
+			h.appendPrettyNodes(sb, n.children, prefix+cont, true)
+			continue
+		}
+		if pad := width - utf8.RuneCountInString(key); pad > 0 {
+			sb.AppendString(strings.Repeat(" ", pad))
+		}
+		sb.AppendString(" =")
+		if text, ok := multilineValue(n.val); ok {
+			sb.AppendByte('\n')
+			for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+				sb.AppendString(prefix)
+				sb.AppendString(cont)
+				sb.AppendString("    ")
+				h.appendStyled(sb, h.styles().Value, line)
+				sb.AppendByte('\n')
+			}
+			continue
+		}
+		sb.AppendByte(' ')
+		h.appendStyledVal(sb, n.val)
+		sb.AppendByte('\n')
+	}
+}
+
+func prettyKey(key string) string {
+	if needsQuoting(key) {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+// multilineValue 返回包含换行的字符串或 error 文本，这类值逐行缩进输出而非转义。
+func multilineValue(v slog.Value) (string, bool) {
+	var s string
+	switch v.Kind() {
+	case slog.KindString:
+		s = v.String()
+	case slog.KindAny:
+		err, ok := v.Any().(error)
+		if !ok {
+			return "", false
+		}
+		s = err.Error()
+	default:
+		return "", false
+	}
+	return s, strings.Contains(s, "\n")
+}
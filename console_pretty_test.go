@@ -0,0 +1,49 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsolePrettyTree(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, true, &HandlerOptions{Level: LevelInfo}, WithPretty(PrettyOptions{AlignKeys: true}))
+	logger := New(h).With("service", "api").WithGroup("http")
+	logger.Info("request done",
+		"method", "GET",
+		Group("resp", "status", 200, "size", 12),
+		"body", "line one\nline two",
+	)
+	New(h).Error("failed", "err", errors.New("boom"))
+
+	got := buf.String()
+	if !strings.Contains(got, "[I] request done\n  service = api\n  http\n  ├─ method = GET\n  ├─ resp\n  │  ├─ status = 200\n  │  └─ size   = 12\n") {
+		t.Fatalf("unexpected tree:\n%s", got)
+	}
+	if !strings.Contains(got, "  └─ body   =\n         line one\n         line two\n") {
+		t.Fatalf("multi-line value must be indented:\n%s", got)
+	}
+	if !strings.Contains(got, "[E] failed\n  err = boom\n") {
+		t.Fatalf("records must start on their own line:\n%s", got)
+	}
+}
+
+func TestConsolePrettyDynamicStaysSingleLine(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, true, nil, WithPretty(PrettyOptions{})).(*handler)
+	h.dynamicTTY = true
+
+	ctx := withDynamicRender(context.Background(), dynamicRenderState{final: true})
+	r := NewRecord(time.Time{}, LevelInfo, "working", 0)
+	r.AddAttrs(Int("pct", 50))
+	if err := h.Handle(ctx, r); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if got := buf.String(); got != "\r\x1b[K[I] working pct=50\n" {
+		t.Fatalf("dynamic render must stay single-line, got %q", got)
+	}
+}
@@ -0,0 +1,133 @@
+package slog
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SourcePathStyle 决定控制台源码位置中文件路径的显示方式。
+type SourcePathStyle int
+
+const (
+	// SourcePathBase 只显示文件名（默认），如 handler.go。
+	SourcePathBase SourcePathStyle = iota
+	// SourcePathShort 显示最后一级目录与文件名，如 slog/handler.go。
+	SourcePathShort
+	// SourcePathModule 显示相对模块根目录（go.mod 所在目录）的路径，如 modules/syslog/syslog.go。
+	SourcePathModule
+	// SourcePathFull 显示完整路径。
+	SourcePathFull
+)
+
+// SourceOptions 配置控制台输出中的源码位置（需启用 AddSource）。
+type SourceOptions struct {
+	Path     SourcePathStyle
+	Function bool // 追加函数名，如 [handler.go:42 slog.(*handler).Handle]
+	// ModuleRoot 为 SourcePathModule 裁剪的根目录，为空时向上查找 go.mod 自动确定。
+	ModuleRoot string
+	// Hyperlink 为 OSC-8 终端超链接模板，支持 {path}（绝对路径）、{line}、{func} 占位符，
+	// 如 "vscode://file/{path}:{line}"。仅在写入交互式终端时生效。
+	Hyperlink string
+}
+
+// WithSource 设置源码位置的显示格式。
+func WithSource(opts SourceOptions) ConsoleOption {
+	return func(c *consoleConfig) {
+		c.source = &opts
+	}
+}
+
+// newSourceAttr 按 SourceOptions 格式化调用位置，默认输出 [file:line]。
+func (h *handler) newSourceAttr(pc uintptr) string {
+	f := frame(pc)
+	opts := h.source
+	if opts == nil {
+		return "[" + filepath.Base(f.File) + ":" + strconv.Itoa(f.Line) + "]"
+	}
+
+	var path string
+	switch opts.Path {
+	case SourcePathShort:
+		path = shortStackFile(f.File)
+	case SourcePathModule:
+		path = moduleRelativePath(f.File, opts.ModuleRoot)
+	case SourcePathFull:
+		path = f.File
+	default:
+		path = filepath.Base(f.File)
+	}
+	location := path + ":" + strconv.Itoa(f.Line)
+	if h.hyperlinks && opts.Hyperlink != "" {
+		location = osc8Link(expandSourceTemplate(opts.Hyperlink, f.File, f.Line, f.Function), location)
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('[')
+	sb.WriteString(location)
+	if opts.Function && f.Function != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(shortFunctionName(f.Function))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// shortFunctionName 去掉函数名中的导入路径目录，保留 pkg.Func / pkg.(*T).Method。
+func shortFunctionName(fn string) string {
+	if i := strings.LastIndexByte(fn, '/'); i >= 0 {
+		return fn[i+1:]
+	}
+	return fn
+}
+
+func expandSourceTemplate(tmpl, file string, line int, fn string) string {
+	return strings.NewReplacer(
+		"{path}", filepath.ToSlash(file),
+		"{line}", strconv.Itoa(line),
+		"{func}", fn,
+	).Replace(tmpl)
+}
+
+// osc8Link 用 OSC-8 转义序列把 text 包装为指向 url 的终端超链接。
+func osc8Link(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// moduleRoots 缓存目录到模块根目录的查找结果，空字符串表示未找到。
+var moduleRoots sync.Map
+
+// moduleRelativePath 返回 file 相对模块根目录的路径；root 为空时向上查找 go.mod，找不到时退回 SourcePathShort。
+func moduleRelativePath(file, root string) string {
+	if root == "" {
+		root = findModuleRoot(filepath.Dir(file))
+	}
+	if root != "" {
+		if rel, err := filepath.Rel(root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return shortStackFile(file)
+}
+
+func findModuleRoot(dir string) string {
+	if v, ok := moduleRoots.Load(dir); ok {
+		return v.(string)
+	}
+	root := ""
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			root = d
+			break
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+	moduleRoots.Store(dir, root)
+	return root
+}
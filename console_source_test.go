@@ -0,0 +1,56 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func handleWithCaller(h Handler) {
+	pc, _, _, _ := runtime.Caller(0)
+	_ = h.Handle(context.Background(), NewRecord(time.Time{}, LevelInfo, "msg", pc))
+}
+
+func TestConsoleSourceFormats(t *testing.T) {
+	opts := &HandlerOptions{AddSource: true}
+	cases := []struct {
+		source SourceOptions
+		want   string
+	}{
+		{SourceOptions{}, "[console_source_test.go:"},
+		{SourceOptions{Path: SourcePathShort}, "/console_source_test.go:"},
+		{SourceOptions{Path: SourcePathModule}, "[console_source_test.go:"},
+		{SourceOptions{Path: SourcePathModule, ModuleRoot: "/nonexistent"}, "/console_source_test.go:"},
+		{SourceOptions{Function: true}, " slog.handleWithCaller]"},
+	}
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		handleWithCaller(NewConsoleHandler(&buf, true, opts, WithSource(tc.source)))
+		if !strings.Contains(buf.String(), tc.want) {
+			t.Fatalf("%+v: expected %q in %q", tc.source, tc.want, buf.String())
+		}
+	}
+}
+
+func TestConsoleSourceHyperlink(t *testing.T) {
+	var buf bytes.Buffer
+	source := SourceOptions{Hyperlink: "vscode://file/{path}:{line}"}
+	h := NewConsoleHandler(&buf, true, &HandlerOptions{AddSource: true}, WithSource(source)).(*handler)
+
+	handleWithCaller(h)
+	if strings.Contains(buf.String(), "\x1b]8;;") {
+		t.Fatalf("hyperlinks must be disabled for non-interactive writers: %q", buf.String())
+	}
+
+	buf.Reset()
+	h.hyperlinks = true
+	handleWithCaller(h)
+	out := buf.String()
+	if !strings.Contains(out, "\x1b]8;;vscode://file/") || !strings.Contains(out, "console_source_test.go:") ||
+		!strings.Contains(out, "\x1b]8;;\x1b\\]") {
+		t.Fatalf("expected OSC-8 hyperlink, got %q", out)
+	}
+}
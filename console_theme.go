@@ -0,0 +1,244 @@
+package slog
+
+import (
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Theme 定义控制台输出各部分的 ANSI 样式，空字符串表示不着色。
+type Theme struct {
+	Name    string
+	Time    string
+	Source  string
+	Message string
+	Key     string
+	Value   string
+	Stack   string
+	// Levels 按级别覆盖标签与消息样式；未列出的级别取不高于它的最近条目，
+	// 仍未命中时标签使用 RegisterLevel 注册的颜色。
+	Levels map[Level]ThemeLevel
+}
+
+// ThemeLevel 是某一级别的标签与消息样式，Message 为空时使用 Theme.Message。
+type ThemeLevel struct {
+	Label   string
+	Message string
+}
+
+// 内置主题名称。
+const (
+	ThemeNameDefault        = "default"
+	ThemeNameSolarized      = "solarized"
+	ThemeNameMonochromeBold = "monochrome-bold"
+)
+
+const (
+	ansiBold = "\033[1m"
+
+	solarizedBase01  = "\033[38;5;240m"
+	solarizedBase0   = "\033[38;5;244m"
+	solarizedYellow  = "\033[38;5;136m"
+	solarizedRed     = "\033[38;5;160m"
+	solarizedMagenta = "\033[38;5;125m"
+	solarizedViolet  = "\033[38;5;61m"
+	solarizedBlue    = "\033[38;5;33m"
+	solarizedCyan    = "\033[38;5;37m"
+	solarizedGreen   = "\033[38;5;64m"
+)
+
+// 内置主题，只读；对外通过 ThemeDefault 等函数返回副本。
+var (
+	themeDefault = &Theme{
+		Name:  ThemeNameDefault,
+		Stack: ansiFaint,
+	}
+	themeSolarized = &Theme{
+		Name:   ThemeNameSolarized,
+		Time:   solarizedBase01,
+		Source: solarizedBase01,
+		Key:    solarizedBlue,
+		Value:  solarizedCyan,
+		Stack:  solarizedBase01,
+		Levels: map[Level]ThemeLevel{
+			LevelTrace: {Label: solarizedBase0},
+			LevelDebug: {Label: solarizedViolet},
+			LevelInfo:  {Label: solarizedGreen},
+			LevelWarn:  {Label: solarizedYellow},
+			LevelError: {Label: solarizedRed, Message: solarizedRed},
+			LevelFatal: {Label: solarizedMagenta, Message: solarizedMagenta},
+		},
+	}
+	themeMonochromeBold = &Theme{
+		Name:   ThemeNameMonochromeBold,
+		Time:   ansiFaint,
+		Source: ansiFaint,
+		Key:    ansiFaint,
+		Stack:  ansiFaint,
+		Levels: map[Level]ThemeLevel{
+			LevelTrace: {Label: ansiFaint},
+			LevelDebug: {Label: ansiFaint},
+			LevelInfo:  {Label: ansiBold},
+			LevelWarn:  {Label: ansiBold},
+			LevelError: {Label: ansiBold, Message: ansiBold},
+		},
+	}
+)
+
+// ThemeDefault 返回默认主题的副本，与未使用主题时的输出一致。
+func ThemeDefault() *Theme { return themeDefault.Clone() }
+
+// ThemeSolarized 返回 solarized 主题的副本。
+func ThemeSolarized() *Theme { return themeSolarized.Clone() }
+
+// ThemeMonochromeBold 返回只使用粗体 / 暗淡样式的主题副本。
+func ThemeMonochromeBold() *Theme { return themeMonochromeBold.Clone() }
+
+// Clone 返回主题的深拷贝，修改副本不影响原主题。
+func (t *Theme) Clone() *Theme {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	c.Levels = maps.Clone(t.Levels)
+	return &c
+}
+
+var themes = struct {
+	sync.RWMutex
+	byName map[string]*Theme
+}{byName: map[string]*Theme{
+	ThemeNameDefault:        themeDefault,
+	ThemeNameSolarized:      themeSolarized,
+	ThemeNameMonochromeBold: themeMonochromeBold,
+}}
+
+// RegisterTheme 注册自定义主题的副本，同名主题会被覆盖。
+func RegisterTheme(theme *Theme) error {
+	if theme == nil || theme.Name == "" {
+		return NewInvalidInputError("theme.Name", "non-empty theme name", "empty")
+	}
+	themes.Lock()
+	themes.byName[strings.ToLower(theme.Name)] = theme.Clone()
+	themes.Unlock()
+	return nil
+}
+
+// LookupTheme 按名称查找主题（不区分大小写），返回副本。
+func LookupTheme(name string) (*Theme, bool) {
+	t, ok := lookupTheme(name)
+	return t.Clone(), ok
+}
+
+func lookupTheme(name string) (*Theme, bool) {
+	themes.RLock()
+	defer themes.RUnlock()
+	t, ok := themes.byName[strings.ToLower(name)]
+	return t, ok
+}
+
+// Themes 返回已注册的主题名称，按字母排序。
+func Themes() []string {
+	themes.RLock()
+	defer themes.RUnlock()
+	names := make([]string, 0, len(themes.byName))
+	for name := range themes.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// level 返回级别对应的标签与消息样式。
+func (t *Theme) level(level Level) ThemeLevel {
+	var out ThemeLevel
+	var best Level
+	found := false
+	for l, style := range t.Levels {
+		if l <= level && (!found || l > best) {
+			out, best, found = style, l, true
+		}
+	}
+	if out.Label == "" {
+		out.Label = levelColor(level)
+	}
+	if out.Message == "" {
+		out.Message = t.Message
+	}
+	return out
+}
+
+// ColorMode 决定控制台输出是否着色。
+type ColorMode int
+
+const (
+	// ColorDefault 默认着色，NO_COLOR 或 TERM=dumb 时关闭；不检测终端，写入文件或管道同样着色。
+	ColorDefault ColorMode = iota
+	// ColorAuto 仅在交互式终端着色；FORCE_COLOR 强制开启，NO_COLOR 与 TERM=dumb 关闭。
+	ColorAuto
+	// ColorAlways 始终着色，忽略环境变量。
+	ColorAlways
+	// ColorNever 从不着色。
+	ColorNever
+)
+
+// colorEnabled 按模式与环境变量决定是否为 w 着色。
+func colorEnabled(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if v := os.Getenv("FORCE_COLOR"); v != "" && v != "0" && v != "false" {
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if mode == ColorAuto {
+		return isInteractiveWriter(w)
+	}
+	return true
+}
+
+// consoleConfig 汇总 ConsoleOption 设置的控制台 handler 选项。
+type consoleConfig struct {
+	theme  *Theme
+	color  ColorMode
+	pretty *PrettyOptions
+	source *SourceOptions
+	time   *timeSettings
+}
+
+// ConsoleOption 配置 NewConsoleHandler 创建的控制台 handler。
+type ConsoleOption func(*consoleConfig)
+
+// WithTheme 设置控制台主题（保存副本），nil 时使用默认主题。
+func WithTheme(theme *Theme) ConsoleOption {
+	theme = theme.Clone()
+	return func(c *consoleConfig) {
+		if theme != nil {
+			c.theme = theme
+		}
+	}
+}
+
+// WithThemeName 按名称选择已注册的主题，未知名称保持当前主题。
+func WithThemeName(name string) ConsoleOption {
+	return func(c *consoleConfig) {
+		if theme, ok := lookupTheme(name); ok {
+			c.theme = theme
+		}
+	}
+}
+
+// WithColorMode 设置着色模式，覆盖 NewConsoleHandler 的 noColor 参数。
+func WithColorMode(mode ColorMode) ConsoleOption {
+	return func(c *consoleConfig) {
+		c.color = mode
+	}
+}
@@ -0,0 +1,100 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConsoleThemeStyles(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, false, &HandlerOptions{Level: LevelTrace}, WithThemeName(ThemeNameSolarized))
+	New(h).Error("failed", "user", "alice")
+
+	out := buf.String()
+	for _, want := range []string{
+		solarizedRed + "[E]" + ansiReset,
+		solarizedRed + "failed" + ansiReset,
+		solarizedBlue + "user=" + ansiReset,
+		solarizedCyan + "alice" + ansiReset,
+		solarizedBase01,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in %q", want, out)
+		}
+	}
+
+	buf.Reset()
+	New(NewConsoleHandler(&buf, false, nil, WithTheme(ThemeMonochromeBold()))).Info("ok")
+	if !strings.Contains(buf.String(), ansiBold+"[I]"+ansiReset) || strings.Contains(buf.String(), ansiBrightGreen) {
+		t.Fatalf("monochrome theme must only use bold/faint: %q", buf.String())
+	}
+}
+
+func TestConsoleColorModeEnv(t *testing.T) {
+	cases := []struct {
+		name          string
+		noColor, term string
+		force         string
+		mode          ColorMode
+		want          bool
+	}{
+		{name: "default", term: "xterm", mode: ColorDefault, want: true},
+		{name: "no-color", noColor: "1", term: "xterm", mode: ColorDefault, want: false},
+		{name: "dumb", term: "dumb", mode: ColorDefault, want: false},
+		{name: "auto non-tty", term: "xterm", mode: ColorAuto, want: false},
+		{name: "force", noColor: "1", term: "dumb", force: "1", mode: ColorAuto, want: true},
+		{name: "always", noColor: "1", mode: ColorAlways, want: true},
+		{name: "never", force: "1", mode: ColorNever, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			t.Setenv("TERM", tc.term)
+			t.Setenv("FORCE_COLOR", tc.force)
+
+			var buf bytes.Buffer
+			New(NewConsoleHandler(&buf, false, nil, WithColorMode(tc.mode))).Info("x")
+			if got := strings.Contains(buf.String(), "\033["); got != tc.want {
+				t.Fatalf("colored = %v, want %v: %q", got, tc.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestThemesAreCopies(t *testing.T) {
+	theme := ThemeSolarized()
+	theme.Key = "changed"
+	theme.Levels[LevelInfo] = ThemeLevel{Label: "changed"}
+	if again := ThemeSolarized(); again.Key == "changed" || again.Levels[LevelInfo].Label == "changed" {
+		t.Fatal("ThemeSolarized must return an independent copy")
+	}
+	looked, _ := LookupTheme(ThemeNameSolarized)
+	looked.Levels[LevelWarn] = ThemeLevel{Label: "changed"}
+	if again, _ := LookupTheme(ThemeNameSolarized); again.Levels[LevelWarn].Label == "changed" {
+		t.Fatal("LookupTheme must return an independent copy")
+	}
+}
+
+func TestColoredLoggerRendersColorForNonTTY(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+	t.Setenv("FORCE_COLOR", "")
+	resetForTest()
+	defer resetForTest()
+
+	records, cancel := Subscribe(1)
+	defer cancel()
+
+	var buf bytes.Buffer
+	NewLogger(&buf, false, false).Info("colored")
+	if !strings.Contains(buf.String(), ansiBrightGreen+"[I]") {
+		t.Fatalf("explicit noColor=false must color non-terminal writers: %q", buf.String())
+	}
+	if event := <-records; !strings.Contains(event.Rendered, ansiBrightGreen+"[I]") {
+		t.Fatalf("subscription rendering must be colored: %q", event.Rendered)
+	}
+}
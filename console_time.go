@@ -0,0 +1,61 @@
+package slog
+
+import (
+	"strconv"
+	"time"
+)
+
+// timeSettings 是单个 handler 的时间显示设置，创建后不再受全局 TimeFormat 影响。
+type timeSettings struct {
+	layout   string
+	location *time.Location // nil 表示保持记录自身的时区
+	relative bool
+	start    time.Time
+	fallback string // 创建时的全局格式，用于识别通用格式化器生成的时间字符串
+}
+
+// format 按设置格式化 t；相对时间输出为 "+1.203s"，表示距 start 的秒数。
+func (s *timeSettings) format(t time.Time) string {
+	if s.relative {
+		d := t.Sub(s.start)
+		sign := "+"
+		if d < 0 {
+			sign, d = "-", -d
+		}
+		return sign + strconv.FormatFloat(d.Seconds(), 'f', 3, 64) + "s"
+	}
+	if s.location != nil {
+		t = t.In(s.location)
+	}
+	return t.Format(s.layout)
+}
+
+func (c *consoleConfig) timeSettings() *timeSettings {
+	if c.time == nil {
+		c.time = &timeSettings{}
+	}
+	return c.time
+}
+
+// WithTimeFormat 设置该 handler 的时间格式，为空时使用创建时的全局 TimeFormat。
+func WithTimeFormat(layout string) ConsoleOption {
+	return func(c *consoleConfig) {
+		c.timeSettings().layout = layout
+	}
+}
+
+// WithTimeZone 把时间转换到 loc 后再格式化，nil 表示保持记录时间的时区。
+func WithTimeZone(loc *time.Location) ConsoleOption {
+	return func(c *consoleConfig) {
+		c.timeSettings().location = loc
+	}
+}
+
+// WithRelativeTime 以距 start 的相对时间（如 "+1.203s"）代替时间戳，start 为零值时取 handler 创建时间。
+func WithRelativeTime(start time.Time) ConsoleOption {
+	return func(c *consoleConfig) {
+		ts := c.timeSettings()
+		ts.relative = true
+		ts.start = start
+	}
+}
@@ -0,0 +1,142 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConsoleTimeSettings(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 10, 30, 0, 0, time.FixedZone("CST", 8*3600))
+
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, true, NewOptions(nil), WithTimeFormat(time.RFC3339), WithTimeZone(time.UTC))
+	_ = h.Handle(context.Background(), NewRecord(ts, LevelInfo, "utc", 0))
+	if !strings.HasPrefix(buf.String(), "2026-01-02T02:30:00Z [I] utc") {
+		t.Fatalf("unexpected UTC output: %q", buf.String())
+	}
+
+	buf.Reset()
+	start := ts.Add(-1203 * time.Millisecond)
+	h = NewConsoleHandler(&buf, true, nil, WithRelativeTime(start))
+	_ = h.Handle(context.Background(), NewRecord(ts, LevelInfo, "rel", 0))
+	if !strings.HasPrefix(buf.String(), "+1.203s [I] rel") {
+		t.Fatalf("unexpected relative output: %q", buf.String())
+	}
+}
+
+func TestConfigTimeDoesNotLeak(t *testing.T) {
+	resetForTest()
+	original := GetTimeFormat()
+	defer SetTimeFormat(original)
+
+	var fileBuf, termBuf bytes.Buffer
+	fileCfg := DefaultConfig()
+	fileCfg.SetEnableText(false)
+	fileCfg.SetEnableJSON(true)
+	fileCfg.TimeFormat = time.RFC3339
+	fileCfg.TimeZone = time.UTC
+	fileLogger := NewLoggerWithConfig(&fileBuf, fileCfg)
+
+	termCfg := DefaultConfig()
+	termCfg.NoColor = true
+	termCfg.SetEnableText(true)
+	termCfg.SetEnableJSON(false)
+	termCfg.TimeFormat = time.Kitchen
+	termLogger := NewLoggerWithConfig(&termBuf, termCfg)
+
+	SetTimeFormat("2006")
+	fileLogger.Info("to file")
+	termLogger.Info("to terminal")
+
+	var payload map[string]any
+	if err := json.Unmarshal(fileBuf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", fileBuf.String(), err)
+	}
+	if ts, _ := payload["time"].(string); !strings.HasSuffix(ts, "Z") || !strings.Contains(ts, "T") {
+		t.Fatalf("file logger must keep its own UTC RFC3339 format, got %q", ts)
+	}
+	if !regexp.MustCompile(`^\d{1,2}:\d{2}(AM|PM) \[I\] `).MatchString(termBuf.String()) {
+		t.Fatalf("terminal logger must keep its own format, got %q", termBuf.String())
+	}
+}
+
+func TestConsoleTimeSettingsReplaceAttrGetsTime(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)
+	var got time.Time
+	opts := NewOptions(&HandlerOptions{ReplaceAttr: func(groups []string, a Attr) Attr {
+		if len(groups) == 0 && a.Key == TimeKey {
+			got = a.Value.Time() // 值必须仍是 time.Time，否则这里会 panic
+		}
+		return a
+	}})
+
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, true, opts, WithTimeFormat(time.Kitchen))
+	_ = h.Handle(context.Background(), NewRecord(ts, LevelInfo, "kitchen", 0))
+	if !got.Equal(ts) || !strings.HasPrefix(buf.String(), "10:30AM [I] kitchen") {
+		t.Fatalf("got time %v, output %q", got, buf.String())
+	}
+
+	buf.Reset()
+	custom := NewOptions(&HandlerOptions{ReplaceAttr: func(groups []string, a Attr) Attr {
+		if len(groups) == 0 && a.Key == TimeKey {
+			return String(TimeKey, "custom")
+		}
+		return a
+	}})
+	h = NewConsoleHandler(&buf, true, custom, WithRelativeTime(ts))
+	_ = h.Handle(context.Background(), NewRecord(ts, LevelInfo, "rel", 0))
+	if !strings.HasPrefix(buf.String(), "custom [I] rel") {
+		t.Fatalf("custom time string should be kept: %q", buf.String())
+	}
+}
+
+func TestSetTimeFormatConcurrent(t *testing.T) {
+	original := GetTimeFormat()
+	defer SetTimeFormat(original)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetTimeFormat(time.RFC3339)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = NewConsoleHandler(io.Discard, true, NewOptions(nil))
+		}()
+	}
+	wg.Wait()
+	if GetTimeFormat() != time.RFC3339 {
+		t.Fatalf("unexpected time format %q", GetTimeFormat())
+	}
+}
+
+func TestTimeFormatVarAndSetTimeFormatLastWins(t *testing.T) {
+	origVar, origOverride := TimeFormat, timeFormatOverride.Load()
+	defer func() {
+		TimeFormat = origVar
+		timeFormatOverride.Store(origOverride)
+	}()
+
+	SetTimeFormat(time.Kitchen)
+	if GetTimeFormat() != time.Kitchen {
+		t.Fatalf("SetTimeFormat not applied: %q", GetTimeFormat())
+	}
+	TimeFormat = time.RFC822
+	if GetTimeFormat() != time.RFC822 {
+		t.Fatalf("assigning TimeFormat after SetTimeFormat must take effect, got %q", GetTimeFormat())
+	}
+	SetTimeFormat(time.Stamp)
+	if GetTimeFormat() != time.Stamp {
+		t.Fatalf("later SetTimeFormat must win, got %q", GetTimeFormat())
+	}
+}
@@ -19,6 +19,10 @@ const (
 type Fields struct {
 	values map[string]any
 	mu     sync.RWMutex
+
+	// levelOverride 为当前上下文单独放开的最低级别，仅在 hasLevel 为 true 时生效。
+	levelOverride Level
+	hasLevel      bool
 }
 
 // fieldsPool 对象池
@@ -41,6 +45,7 @@ func (f *Fields) clone() *Fields {
 	if f != nil {
 		f.mu.RLock()
 		maps.Copy(newF.values, f.values)
+		newF.levelOverride, newF.hasLevel = f.levelOverride, f.hasLevel
 		f.mu.RUnlock()
 	}
 	return newF
@@ -107,6 +112,40 @@ func (l *Logger) WithValue(key string, val any) *Logger {
 	return newLogger
 }
 
+// WithLevelOverride 返回仅对当前 Logger 上下文放开到 level 的新 Logger。
+func (l *Logger) WithLevelOverride(level Level) *Logger {
+	newLogger := l.clone()
+	if newLogger.ctx == nil {
+		newLogger.ctx = context.Background()
+	}
+	newLogger.ctx = WithLevelOverride(newLogger.ctx, level)
+	return newLogger
+}
+
+// WithLevelOverride 在上下文字段中记录级别覆盖，低于全局级别但不低于 level 的记录将为该上下文输出。
+func WithLevelOverride(ctx context.Context, level Level) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	newFields := getFields(ctx).clone()
+	newFields.mu.Lock()
+	newFields.levelOverride = level
+	newFields.hasLevel = true
+	newFields.mu.Unlock()
+	return context.WithValue(ctx, fieldsKey, newFields)
+}
+
+// WithoutLevelOverride 移除上下文中的级别覆盖。
+func WithoutLevelOverride(ctx context.Context) context.Context {
+	fields := getFields(ctx)
+	if fields == nil {
+		return ctx
+	}
+	newFields := fields.clone()
+	newFields.hasLevel = false
+	return context.WithValue(ctx, fieldsKey, newFields)
+}
+
 // WithTimeout 创建带超时的Logger
 func (l *Logger) WithTimeout(timeout time.Duration) (*Logger, context.CancelFunc) {
 	ctx, cancel := context.WithTimeout(l.ctx, timeout)
@@ -0,0 +1,298 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DropRule 声明式丢弃规则，所有已设置的条件同时满足时丢弃记录。
+type DropRule struct {
+	// Name 规则名，全局唯一，用于运行时启停与统计。
+	Name string
+	// MessagePrefix 消息前缀匹配。
+	MessagePrefix string
+	// MessagePattern 消息正则匹配。
+	MessagePattern string
+	// Attrs 属性匹配，分组键以 "." 连接，值按字符串比较；也会匹配 WithValue 设置的上下文字段。
+	// Lazy 属性按其（单条记录内共享的）求值结果匹配，其他 LogValuer 属性不参与匹配，避免额外求值。
+	Attrs map[string]string
+	// MinLevel / MaxLevel 级别范围（闭区间），nil 表示不限。
+	MinLevel Leveler
+	MaxLevel Leveler
+	// Module Logger 模块名（Default("a", "b") 即 "a.b"），同时匹配其子模块。
+	Module string
+}
+
+// DropRuleStats 描述规则状态与命中次数。
+type DropRuleStats struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Hits    uint64 `json:"hits"`
+}
+
+type dropRuleEntry struct {
+	rule     DropRule
+	pattern  *regexp.Regexp
+	disabled atomic.Bool
+	hits     atomic.Uint64
+}
+
+var (
+	dropRulesMu sync.Mutex
+	dropRules   atomic.Pointer[[]*dropRuleEntry]
+)
+
+// AddDropRule 注册丢弃规则；同名规则会被替换并重置计数。
+func AddDropRule(rule DropRule) error {
+	if rule.Name == "" {
+		return NewInvalidInputError("rule.Name", "non-empty rule name", "empty")
+	}
+	if rule.MessagePrefix == "" && rule.MessagePattern == "" && len(rule.Attrs) == 0 &&
+		rule.MinLevel == nil && rule.MaxLevel == nil && rule.Module == "" {
+		return NewInvalidInputError("rule", "at least one condition", "none")
+	}
+	entry := &dropRuleEntry{rule: rule}
+	if rule.MessagePattern != "" {
+		re, err := regexp.Compile(rule.MessagePattern)
+		if err != nil {
+			return NewConfigurationError("drop_rules", "MessagePattern", err)
+		}
+		entry.pattern = re
+	}
+
+	dropRulesMu.Lock()
+	defer dropRulesMu.Unlock()
+	rules := slices.DeleteFunc(slices.Clone(currentDropRules()), func(e *dropRuleEntry) bool {
+		return e.rule.Name == rule.Name
+	})
+	rules = append(rules, entry)
+	dropRules.Store(&rules)
+	return nil
+}
+
+// RemoveDropRule 移除规则。
+func RemoveDropRule(name string) bool {
+	dropRulesMu.Lock()
+	defer dropRulesMu.Unlock()
+	current := currentDropRules()
+	rules := slices.DeleteFunc(slices.Clone(current), func(e *dropRuleEntry) bool {
+		return e.rule.Name == name
+	})
+	if len(rules) == len(current) {
+		return false
+	}
+	dropRules.Store(&rules)
+	return true
+}
+
+// ClearDropRules 移除全部规则。
+func ClearDropRules() {
+	dropRulesMu.Lock()
+	defer dropRulesMu.Unlock()
+	dropRules.Store(nil)
+}
+
+// DisableDropRules 临时停用指定规则。
+func DisableDropRules(names ...string) {
+	for _, name := range names {
+		SetDropRuleEnabled(name, false)
+	}
+}
+
+// EnableDropRules 重新启用指定规则。
+func EnableDropRules(names ...string) {
+	for _, name := range names {
+		SetDropRuleEnabled(name, true)
+	}
+}
+
+// SetDropRuleEnabled 设置规则启用状态。
+func SetDropRuleEnabled(name string, enabled bool) {
+	if entry := findDropRule(name); entry != nil {
+		entry.disabled.Store(!enabled)
+	}
+}
+
+// IsDropRuleDisabled 判断规则是否被停用；不存在的规则返回 false。
+func IsDropRuleDisabled(name string) bool {
+	entry := findDropRule(name)
+	return entry != nil && entry.disabled.Load()
+}
+
+// GetDropRuleStats 返回全部规则的状态与命中次数（按注册顺序）。
+func GetDropRuleStats() []DropRuleStats {
+	rules := currentDropRules()
+	stats := make([]DropRuleStats, 0, len(rules))
+	for _, entry := range rules {
+		stats = append(stats, DropRuleStats{
+			Name:    entry.rule.Name,
+			Enabled: !entry.disabled.Load(),
+			Hits:    entry.hits.Load(),
+		})
+	}
+	return stats
+}
+
+func currentDropRules() []*dropRuleEntry {
+	if rules := dropRules.Load(); rules != nil {
+		return *rules
+	}
+	return nil
+}
+
+func findDropRule(name string) *dropRuleEntry {
+	for _, entry := range currentDropRules() {
+		if entry.rule.Name == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// matchDropRules 返回第一个命中的规则，并累加其命中计数。
+func (l *Logger) matchDropRules(ctx context.Context, r slog.Record) bool {
+	rules := currentDropRules()
+	if len(rules) == 0 {
+		return false
+	}
+	match := dropRuleMatch{ctx: ctx, record: r, logger: l}
+	for _, entry := range rules {
+		if entry.disabled.Load() || !entry.matches(&match) {
+			continue
+		}
+		entry.hits.Add(1)
+		return true
+	}
+	return false
+}
+
+// dropRuleMatch 延迟计算规则匹配所需的模块名与属性映射，多条规则共享。
+type dropRuleMatch struct {
+	ctx    context.Context
+	record slog.Record
+	logger *Logger
+
+	module    string
+	moduleSet bool
+	attrs     map[string]string
+}
+
+func (m *dropRuleMatch) moduleName() string {
+	if !m.moduleSet {
+		m.module = m.logger.moduleName()
+		if m.module == "" {
+			m.record.Attrs(func(a slog.Attr) bool {
+				if a.Key == "$module" {
+					m.module = a.Value.String()
+					return false
+				}
+				return true
+			})
+		}
+		m.moduleSet = true
+	}
+	return m.module
+}
+
+func (m *dropRuleMatch) attrValue(key string) (string, bool) {
+	if m.attrs == nil {
+		m.attrs = make(map[string]string, m.record.NumAttrs())
+		flattenRuleAttrValues(m.attrs, strings.Join(m.logger.groupNames(), "."), RecordAttrs(m.record))
+	}
+	if v, ok := m.attrs[key]; ok {
+		return v, true
+	}
+	if fields := getFields(m.ctx); fields != nil {
+		fields.mu.RLock()
+		v, ok := fields.values[key]
+		fields.mu.RUnlock()
+		if ok {
+			return fmt.Sprint(v), true
+		}
+	}
+	return "", false
+}
+
+func (e *dropRuleEntry) matches(m *dropRuleMatch) bool {
+	rule := &e.rule
+	if rule.MinLevel != nil && m.record.Level < rule.MinLevel.Level() {
+		return false
+	}
+	if rule.MaxLevel != nil && m.record.Level > rule.MaxLevel.Level() {
+		return false
+	}
+	if rule.MessagePrefix != "" && !strings.HasPrefix(m.record.Message, rule.MessagePrefix) {
+		return false
+	}
+	if e.pattern != nil && !e.pattern.MatchString(m.record.Message) {
+		return false
+	}
+	if rule.Module != "" {
+		module := m.moduleName()
+		if module != rule.Module && !strings.HasPrefix(module, rule.Module+".") {
+			return false
+		}
+	}
+	for key, want := range rule.Attrs {
+		if got, ok := m.attrValue(key); !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// moduleName 返回 Default(modules...) 设置的模块前缀。
+func (l *Logger) moduleName() string {
+	if eh := l.addonsHandler(); eh != nil && len(eh.prefixes) > 0 && eh.prefixes[0].Any() != nil {
+		return eh.prefixes[0].String()
+	}
+	return ""
+}
+
+// groupNames 返回 WithGroup 累积的分组路径，记录属性在输出时位于该路径下。
+func (l *Logger) groupNames() []string {
+	if eh := l.addonsHandler(); eh != nil {
+		return eh.groups
+	}
+	return nil
+}
+
+func (l *Logger) addonsHandler() *eHandler {
+	for _, lg := range []*slog.Logger{l.text, l.json} {
+		if lg == nil {
+			continue
+		}
+		if eh, ok := lg.Handler().(*eHandler); ok {
+			return eh
+		}
+	}
+	return nil
+}
+
+// flattenRuleAttrValues 同 flattenAttrValues，但只解析已绑定的 Lazy 属性，跳过其他 LogValuer。
+func flattenRuleAttrValues(dst map[string]string, prefix string, attrs []slog.Attr) {
+	for _, a := range attrs {
+		key := a.Key
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		v := a.Value
+		if v.Kind() == slog.KindLogValuer {
+			if _, ok := v.Any().(*lazyOnce); !ok {
+				continue
+			}
+			v = v.Resolve()
+		}
+		if v.Kind() == slog.KindGroup {
+			flattenRuleAttrValues(dst, key, v.Group())
+			continue
+		}
+		dst[key] = v.String()
+	}
+}
@@ -0,0 +1,127 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDropRulesSuppressMatchingRecords(t *testing.T) {
+	resetForTest()
+	defer ClearDropRules()
+
+	warn := LevelWarn
+	rules := []DropRule{
+		{Name: "healthz", MessagePrefix: "GET /healthz"},
+		{Name: "chatty-lib", Module: "vendor", MaxLevel: &warn},
+		{Name: "bot", MessagePattern: `^request\b`, Attrs: map[string]string{"http.ua": "probe"}},
+	}
+	for _, rule := range rules {
+		if err := AddDropRule(rule); err != nil {
+			t.Fatalf("add %s: %v", rule.Name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false)
+	logger.Info("GET /healthz 200")
+	logger.Info("GET /orders 200")
+	logger.WithGroup("http").Info("request done", "ua", "probe")
+	logger.WithGroup("http").Info("request done", "ua", "browser")
+
+	vendorLogger := logger.With("$module", "vendor.sdk")
+	vendorLogger.Warn("deprecated call")
+	vendorLogger.Error("vendor failure")
+
+	out := buf.String()
+	for _, hidden := range []string{"healthz", "probe", "deprecated call"} {
+		if strings.Contains(out, hidden) {
+			t.Fatalf("expected %q to be dropped:\n%s", hidden, out)
+		}
+	}
+	for _, shown := range []string{"GET /orders", "browser", "vendor failure"} {
+		if !strings.Contains(out, shown) {
+			t.Fatalf("expected %q to be logged:\n%s", shown, out)
+		}
+	}
+
+	if got := Default("vendor", "sdk").moduleName(); got != "vendor.sdk" {
+		t.Fatalf("expected module name from Default prefix, got %q", got)
+	}
+
+	stats := GetDropRuleStats()
+	if len(stats) != 3 || stats[0].Hits != 1 || stats[1].Hits != 1 || stats[2].Hits != 1 {
+		t.Fatalf("unexpected rule stats: %+v", stats)
+	}
+}
+
+func TestDropRulesRuntimeManagement(t *testing.T) {
+	resetForTest()
+	defer ClearDropRules()
+
+	if err := AddDropRule(DropRule{Name: "empty"}); err == nil {
+		t.Fatal("expected rule without conditions to be rejected")
+	}
+	if err := AddDropRule(DropRule{Name: "bad", MessagePattern: "("}); err == nil {
+		t.Fatal("expected invalid regexp to be rejected")
+	}
+	if err := AddDropRule(DropRule{Name: "noise", MessagePrefix: "noise"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false)
+
+	DisableDropRules("noise")
+	if !IsDropRuleDisabled("noise") {
+		t.Fatal("expected rule to be disabled")
+	}
+	logger.Info("noise while disabled")
+	EnableDropRules("noise")
+	logger.Info("noise while enabled")
+
+	out := buf.String()
+	if !strings.Contains(out, "while disabled") || strings.Contains(out, "while enabled") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if !RemoveDropRule("noise") || RemoveDropRule("noise") {
+		t.Fatal("unexpected RemoveDropRule result")
+	}
+}
+
+func TestDropRulesResolveLazyAttrsOnce(t *testing.T) {
+	resetForTest()
+	defer ClearDropRules()
+	if err := AddDropRule(DropRule{Name: "probe", Attrs: map[string]string{"ua": "probe"}}); err != nil {
+		t.Fatalf("add rule: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false)
+	logger.SetLevel(LevelInfo)
+
+	var calls atomic.Int32
+	ua := func(v string) Attr {
+		return Lazy("ua", func() any { calls.Add(1); return v })
+	}
+
+	logger.Debug("disabled", ua("probe"))
+	if calls.Load() != 0 {
+		t.Fatalf("lazy attr must not resolve for disabled records, calls=%d", calls.Load())
+	}
+	if stats := GetDropRuleStats(); stats[0].Hits != 0 {
+		t.Fatalf("disabled records must not count as rule hits: %+v", stats)
+	}
+
+	logger.Info("dropped", ua("probe"))
+	if calls.Load() != 1 || strings.Contains(buf.String(), "dropped") {
+		t.Fatalf("expected rule to match the lazy value once, calls=%d out=%q", calls.Load(), buf.String())
+	}
+
+	calls.Store(0)
+	logger.Info("kept", ua("browser"))
+	if calls.Load() != 1 || !strings.Contains(buf.String(), "ua=browser") {
+		t.Fatalf("surviving record must resolve lazy attr once, calls=%d out=%q", calls.Load(), buf.String())
+	}
+}
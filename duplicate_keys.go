@@ -0,0 +1,73 @@
+package slog
+
+import (
+	"context"
+
+	"github.com/darkit/slog/internal/common"
+)
+
+// DuplicateKeyPolicy 决定 With 绑定属性、上下文字段与记录属性出现同名键时的处理方式。
+type DuplicateKeyPolicy = common.DuplicateKeyPolicy
+
+const (
+	// DuplicateKeysKeepAll 不额外去重，保持原有输出（默认）。
+	DuplicateKeysKeepAll = common.DuplicateKeysKeepAll
+	// DuplicateKeysLastWins 保留首次出现的位置，值取最后一次。
+	DuplicateKeysLastWins = common.DuplicateKeysLastWins
+	// DuplicateKeysFirstWins 保留首次出现的键值。
+	DuplicateKeysFirstWins = common.DuplicateKeysFirstWins
+	// DuplicateKeysSuffix 为后续重复键追加序号，如 user、user#2。
+	DuplicateKeysSuffix = common.DuplicateKeysSuffix
+)
+
+// SetDuplicateKeyPolicy 设置全局重复键策略，作用于未在 Config 中单独指定策略的 Logger，
+// 以及 syslog / webhook 等模块 handler 的 WithAttrs。
+func SetDuplicateKeyPolicy(policy DuplicateKeyPolicy) {
+	common.SetDuplicateKeyPolicy(policy)
+}
+
+// GetDuplicateKeyPolicy 返回当前全局重复键策略。
+func GetDuplicateKeyPolicy() DuplicateKeyPolicy {
+	return common.CurrentDuplicateKeyPolicy()
+}
+
+// SetDuplicateKeyPolicy 为该配置创建的 Logger 单独指定重复键策略。
+func (c *Config) SetDuplicateKeyPolicy(policy DuplicateKeyPolicy) {
+	if c == nil {
+		return
+	}
+	c.DuplicateKeys = &policy
+}
+
+// InheritDuplicateKeyPolicy 恢复为跟随全局重复键策略。
+func (c *Config) InheritDuplicateKeyPolicy() {
+	if c == nil {
+		return
+	}
+	c.DuplicateKeys = nil
+}
+
+// duplicateKeyPolicy 返回 Logger 生效的重复键策略，override 表示来自 Config 而非全局。
+func (l *Logger) duplicateKeyPolicy() (policy DuplicateKeyPolicy, override bool) {
+	if l.config != nil && l.config.DuplicateKeys != nil {
+		return *l.config.DuplicateKeys, true
+	}
+	return GetDuplicateKeyPolicy(), false
+}
+
+type duplicateKeyPolicyKey struct{}
+
+// withDuplicateKeyPolicy 把 Logger 级策略随 ctx 传给 eHandler，用于合并上下文字段。
+func withDuplicateKeyPolicy(ctx context.Context, policy DuplicateKeyPolicy) context.Context {
+	return context.WithValue(ctx, duplicateKeyPolicyKey{}, policy)
+}
+
+// contextDuplicateKeyPolicy 返回 ctx 携带的 Logger 级策略，没有时使用全局策略。
+func contextDuplicateKeyPolicy(ctx context.Context) DuplicateKeyPolicy {
+	if ctx != nil {
+		if policy, ok := ctx.Value(duplicateKeyPolicyKey{}).(DuplicateKeyPolicy); ok {
+			return policy
+		}
+	}
+	return GetDuplicateKeyPolicy()
+}
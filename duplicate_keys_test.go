@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDuplicateKeyPolicyBoundAndContextAttrs(t *testing.T) {
+	resetForTest()
+	defer SetDuplicateKeyPolicy(DuplicateKeysKeepAll)
+
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.NoColor = true
+	cfg.SetEnableText(true)
+	cfg.SetEnableJSON(false)
+	logger := NewLoggerWithConfig(&buf, cfg).With("user", "a").With("user", "b").WithValue("user", "ctx")
+
+	cases := []struct {
+		policy DuplicateKeyPolicy
+		want   []string
+		absent []string
+	}{
+		{DuplicateKeysLastWins, []string{" user=rec"}, []string{"user=a", "user=b", "user=ctx"}},
+		{DuplicateKeysFirstWins, []string{" user=ctx"}, []string{"user=a", "user=b", "user=rec"}},
+		{DuplicateKeysSuffix, []string{" user=ctx", " user#2=a", " user#3=b", " user#4=rec"}, nil},
+		// 默认策略保持原有输出：With 与调用参数全部保留，记录中已有的键不再追加上下文字段。
+		{DuplicateKeysKeepAll, []string{" user=a", " user=b", " user=rec"}, []string{"user=ctx"}},
+	}
+	for _, tc := range cases {
+		SetDuplicateKeyPolicy(tc.policy)
+		buf.Reset()
+		logger.Info("dup", "user", "rec")
+		out := buf.String()
+		for _, w := range tc.want {
+			if !strings.Contains(out, w) {
+				t.Fatalf("%s: expected %q in %q", tc.policy, w, out)
+			}
+		}
+		for _, a := range tc.absent {
+			if strings.Contains(out, a) {
+				t.Fatalf("%s: unexpected %q in %q", tc.policy, a, out)
+			}
+		}
+	}
+}
+
+func TestDuplicateKeyPolicyPerLogger(t *testing.T) {
+	resetForTest()
+	defer SetDuplicateKeyPolicy(DuplicateKeysKeepAll)
+	SetDuplicateKeyPolicy(DuplicateKeysSuffix)
+
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.NoColor = true
+	cfg.SetEnableText(true)
+	cfg.SetEnableJSON(false)
+	cfg.SetDuplicateKeyPolicy(DuplicateKeysFirstWins)
+	logger := NewLoggerWithConfig(&buf, cfg).With("user", "a")
+
+	logger.Info("dup", "user", "rec")
+	if out := buf.String(); !strings.Contains(out, " user=a") || strings.Contains(out, "user=rec") || strings.Contains(out, "user#2") {
+		t.Fatalf("config policy should override global: %q", out)
+	}
+
+	buf.Reset()
+	logger.WithValue("user", "ctx").Info("dup", "user", "rec")
+	if out := buf.String(); !strings.Contains(out, " user=ctx") || strings.Contains(out, "user=a") || strings.Contains(out, "user#2") {
+		t.Fatalf("config policy should apply to context fields: %q", out)
+	}
+
+	buf.Reset()
+	cfg.InheritDuplicateKeyPolicy()
+	logger.Info("dup", "user", "rec")
+	if out := buf.String(); !strings.Contains(out, " user#2=rec") {
+		t.Fatalf("expected global suffix policy: %q", out)
+	}
+}
@@ -0,0 +1,302 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/darkit/slog/internal/common"
+)
+
+const defaultErrorRenderDepth = 8
+
+// ErrorRenderOptions 控制 error 属性的结构化渲染。
+type ErrorRenderOptions struct {
+	Enabled  bool // 开启结构化渲染；默认关闭，error 按 Error() 字符串输出
+	MaxDepth int  // 展开的最大层数，<=0 时使用默认值 8
+}
+
+var errorRenderOptions atomic.Pointer[ErrorRenderOptions]
+
+// SetErrorRenderOptions 设置全局 error 渲染选项。
+func SetErrorRenderOptions(opts ErrorRenderOptions) {
+	errorRenderOptions.Store(&opts)
+}
+
+// GetErrorRenderOptions 返回当前 error 渲染选项。
+func GetErrorRenderOptions() ErrorRenderOptions {
+	if opts := errorRenderOptions.Load(); opts != nil {
+		return *opts
+	}
+	return ErrorRenderOptions{}
+}
+
+func errorRenderDepth() (int, bool) {
+	opts := errorRenderOptions.Load()
+	if opts == nil || !opts.Enabled {
+		return 0, false
+	}
+	if opts.MaxDepth <= 0 {
+		return defaultErrorRenderDepth, true
+	}
+	return opts.MaxDepth, true
+}
+
+// errorValue 是展开后的错误树：JSON 输出为嵌套对象，文本输出为单行摘要。
+type errorValue struct {
+	Message   string         `json:"msg"`
+	Type      string         `json:"type"`
+	Kind      string         `json:"kind,omitempty"`
+	Component string         `json:"component,omitempty"`
+	Operation string         `json:"operation,omitempty"`
+	Field     string         `json:"field,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+	Attrs     map[string]any `json:"attrs,omitempty"`
+	Cause     *errorValue    `json:"cause,omitempty"`
+	Causes    []*errorValue  `json:"causes,omitempty"`
+	Truncated bool           `json:"truncated,omitempty"`
+	Cycle     bool           `json:"cycle,omitempty"`
+}
+
+// MarshalJSON 显式实现，避免 encoding/json 因 MarshalText 退化为字符串。
+func (e *errorValue) MarshalJSON() ([]byte, error) {
+	type plain errorValue
+	return json.Marshal((*plain)(e))
+}
+
+// MarshalText 供控制台与 TextHandler 输出摘要。
+func (e *errorValue) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// String 返回摘要：errors.Join 的分支以 "; " 连接，SlogError 与 LogValuer 字段附在括号内。
+func (e *errorValue) String() string {
+	var sb strings.Builder
+	e.writeSummary(&sb)
+	return sb.String()
+}
+
+func (e *errorValue) writeSummary(sb *strings.Builder) {
+	if len(e.Causes) > 0 && strings.Contains(e.Message, "\n") {
+		for i, c := range e.Causes {
+			if i > 0 {
+				sb.WriteString("; ")
+			}
+			c.writeSummary(sb)
+		}
+	} else {
+		sb.WriteString(e.Message)
+	}
+
+	var meta []string
+	for n := e; n != nil; n = n.Cause {
+		meta = n.appendMeta(meta)
+	}
+	if len(meta) > 0 {
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(meta, " "))
+		sb.WriteByte(')')
+	}
+}
+
+func (e *errorValue) appendMeta(meta []string) []string {
+	if e.Kind != "" {
+		meta = append(meta, "kind="+e.Kind)
+	}
+	if e.Component != "" {
+		meta = append(meta, "component="+e.Component)
+	}
+	if e.Operation != "" {
+		meta = append(meta, "operation="+e.Operation)
+	}
+	meta = appendMapMeta(meta, e.Details)
+	meta = appendMapMeta(meta, e.Attrs)
+	if e.Cycle {
+		meta = append(meta, "cycle")
+	}
+	if e.Truncated {
+		meta = append(meta, "truncated")
+	}
+	return meta
+}
+
+func appendMapMeta(meta []string, m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		meta = append(meta, fmt.Sprintf("%s=%v", k, m[k]))
+	}
+	return meta
+}
+
+// errorFromValue 取出属性值中的 error（含实现了 LogValuer 的 error）。
+func errorFromValue(v slog.Value) (error, bool) {
+	switch v.Kind() {
+	case slog.KindAny, slog.KindLogValuer:
+		err, ok := v.Any().(error)
+		return err, ok && err != nil
+	}
+	return nil, false
+}
+
+// hasErrorStructure 判断 error 是否有可展开的结构；普通叶子错误保持原有字符串输出。
+func hasErrorStructure(err error) bool {
+	switch e := err.(type) {
+	case *SlogError, slog.LogValuer:
+		return true
+	case interface{ Unwrap() error }:
+		return e.Unwrap() != nil
+	case interface{ Unwrap() []error }:
+		return len(e.Unwrap()) > 0
+	}
+	return false
+}
+
+// renderErrorAttr 将属性（含分组内）中的结构化 error 替换为 errorValue。
+func renderErrorAttr(attr slog.Attr, maxDepth int) (slog.Attr, bool) {
+	if err, ok := errorFromValue(attr.Value); ok {
+		if !hasErrorStructure(err) {
+			return attr, false
+		}
+		attr.Value = slog.AnyValue(expandError(err, 0, maxDepth, nil))
+		return attr, true
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		return attr, false
+	}
+	group := attr.Value.Group()
+	var out []slog.Attr
+	for i, child := range group {
+		rendered, changed := renderErrorAttr(child, maxDepth)
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(group)
+		}
+		out[i] = rendered
+	}
+	if out == nil {
+		return attr, false
+	}
+	attr.Value = slog.GroupValue(out...)
+	return attr, true
+}
+
+// attrHasErrorStructure 判断属性是否需要 renderErrorAttr 处理。
+func attrHasErrorStructure(attr slog.Attr) bool {
+	if err, ok := errorFromValue(attr.Value); ok {
+		return hasErrorStructure(err)
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, child := range attr.Value.Group() {
+			if attrHasErrorStructure(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func recordHasErrorStructure(r slog.Record) bool {
+	if _, enabled := errorRenderDepth(); !enabled {
+		return false
+	}
+	found := false
+	r.Attrs(func(attr slog.Attr) bool {
+		found = attrHasErrorStructure(attr)
+		return !found
+	})
+	return found
+}
+
+// expandError 递归展开错误；path 记录祖先指针，用于检测环。
+func expandError(err error, depth, maxDepth int, path []uintptr) *errorValue {
+	node := &errorValue{
+		Message: err.Error(),
+		Type:    reflect.TypeOf(err).String(),
+	}
+
+	if se, ok := err.(*SlogError); ok && se != nil {
+		node.Kind = se.Type.String()
+		node.Component = se.Component
+		node.Operation = se.Operation
+		node.Field = se.Field
+		if details := se.GetDetails(); len(details) > 0 {
+			node.Details = details
+		}
+	}
+	if lv, ok := err.(slog.LogValuer); ok {
+		v := lv.LogValue().Resolve()
+		if v.Kind() == slog.KindGroup {
+			node.Attrs = common.AttrsToMap(v.Group()...)
+		} else {
+			node.Attrs = map[string]any{"value": v.Any()}
+		}
+	}
+
+	var causes []error
+	single := false
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if c := e.Unwrap(); c != nil {
+			causes, single = []error{c}, true
+		}
+	case interface{ Unwrap() []error }:
+		for _, c := range e.Unwrap() {
+			if c != nil {
+				causes = append(causes, c)
+			}
+		}
+	}
+	if len(causes) == 0 {
+		return node
+	}
+	if depth+1 >= maxDepth {
+		node.Truncated = true
+		return node
+	}
+
+	if ptr, ok := errorPointer(err); ok {
+		path = append(path, ptr)
+	}
+	for _, c := range causes {
+		var child *errorValue
+		if ptr, ok := errorPointer(c); ok && slices.Contains(path, ptr) {
+			child = &errorValue{Message: c.Error(), Type: reflect.TypeOf(c).String(), Cycle: true}
+		} else {
+			child = expandError(c, depth+1, maxDepth, path)
+		}
+		if single {
+			node.Cause = child
+		} else {
+			node.Causes = append(node.Causes, child)
+		}
+	}
+	return node
+}
+
+func errorPointer(err error) (uintptr, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return 0, false
+	}
+	return v.Pointer(), true
+}
+
+func renderErrors(attr slog.Attr) slog.Attr {
+	maxDepth, enabled := errorRenderDepth()
+	if !enabled {
+		return attr
+	}
+	attr, _ = renderErrorAttr(attr, maxDepth)
+	return attr
+}
@@ -0,0 +1,175 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type valuerError struct{ id int }
+
+func (e valuerError) Error() string { return "valuer failed" }
+
+func (e valuerError) LogValue() Value {
+	return GroupValue(Int("id", e.id))
+}
+
+type loopError struct{ next error }
+
+func (e *loopError) Error() string { return "loop" }
+
+func (e *loopError) Unwrap() error { return e.next }
+
+func TestErrorRenderJSON(t *testing.T) {
+	resetForTest()
+	SetErrorRenderOptions(ErrorRenderOptions{Enabled: true})
+	defer SetErrorRenderOptions(ErrorRenderOptions{})
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.SetEnableText(false)
+	cfg.SetEnableJSON(true)
+	logger := NewLoggerWithConfig(&buf, cfg)
+
+	cause := NewConfigurationError("db", "dsn", errors.New("empty")).WithDetails("retry", 3)
+	err := fmt.Errorf("startup: %w", errors.Join(cause, valuerError{id: 7}))
+	logger.Error("boot failed", "error", err, "plain", errors.New("leaf"))
+
+	var payload struct {
+		Plain string `json:"plain"`
+		Error struct {
+			Msg   string `json:"msg"`
+			Cause struct {
+				Type   string `json:"type"`
+				Causes []struct {
+					Kind      string         `json:"kind"`
+					Component string         `json:"component"`
+					Details   map[string]any `json:"details"`
+					Attrs     map[string]any `json:"attrs"`
+					Cause     *struct {
+						Msg string `json:"msg"`
+					} `json:"cause"`
+				} `json:"causes"`
+			} `json:"cause"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if payload.Plain != "leaf" {
+		t.Fatalf("leaf errors must stay strings, got %q", payload.Plain)
+	}
+	causes := payload.Error.Cause.Causes
+	if payload.Error.Msg != err.Error() || payload.Error.Cause.Type != "*errors.joinError" || len(causes) != 2 {
+		t.Fatalf("unexpected error tree: %s", buf.String())
+	}
+	if causes[0].Kind != "Configuration" || causes[0].Component != "db" || causes[0].Details["retry"] != float64(3) ||
+		causes[0].Cause == nil || causes[0].Cause.Msg != "empty" {
+		t.Fatalf("unexpected SlogError node: %s", buf.String())
+	}
+	if causes[1].Attrs["id"] != float64(7) {
+		t.Fatalf("unexpected LogValuer node: %s", buf.String())
+	}
+}
+
+func TestErrorRenderConsoleSummary(t *testing.T) {
+	resetForTest()
+	SetErrorRenderOptions(ErrorRenderOptions{Enabled: true})
+	defer SetErrorRenderOptions(ErrorRenderOptions{})
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.NoColor = true
+	cfg.SetEnableText(true)
+	cfg.SetEnableJSON(false)
+	logger := NewLoggerWithConfig(&buf, cfg)
+
+	err := errors.Join(errors.New("a"), NewProcessingError("cache", "get", nil))
+	logger.Error("failed", "err", err)
+
+	want := `err="a; slog/cache: processing failed in operation 'get' (kind=Processing component=cache operation=get)"`
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("expected %s in:\n%s", want, buf.String())
+	}
+}
+
+func TestErrorRenderDepthAndCycle(t *testing.T) {
+	loop := &loopError{}
+	loop.next = loop
+	node := expandError(loop, 0, defaultErrorRenderDepth, nil)
+	if node.Cause == nil || !node.Cause.Cycle {
+		t.Fatalf("expected cycle marker, got %+v", node)
+	}
+
+	deep := errors.New("root")
+	for i := 0; i < 5; i++ {
+		deep = fmt.Errorf("wrap%d: %w", i, deep)
+	}
+	node = expandError(deep, 0, 3, nil)
+	if node.Cause == nil || node.Cause.Cause == nil || !node.Cause.Cause.Truncated || node.Cause.Cause.Cause != nil {
+		t.Fatalf("expected truncation at depth 3, got %+v", node)
+	}
+	if !strings.HasSuffix(node.String(), "(truncated)") {
+		t.Fatalf("expected truncated summary, got %q", node.String())
+	}
+
+	SetErrorRenderOptions(ErrorRenderOptions{})
+	errRecord := slog.NewRecord(time.Now(), LevelError, "m", 0)
+	errRecord.AddAttrs(Any("err", deep))
+	if attr := renderErrors(Any("err", deep)); attr.Value.Any() != deep {
+		t.Fatalf("disabled rendering must keep original error")
+	}
+	if recordHasErrorStructure(errRecord) {
+		t.Fatalf("disabled rendering must not report error structure")
+	}
+}
+
+func TestErrorRenderDisabledByDefault(t *testing.T) {
+	resetForTest()
+	SetErrorRenderOptions(ErrorRenderOptions{})
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.SetEnableText(false)
+	cfg.SetEnableJSON(true)
+	logger := NewLoggerWithConfig(&buf, cfg)
+
+	err := fmt.Errorf("startup: %w", errors.New("empty"))
+	logger.Error("boot failed", "error", err)
+
+	var payload map[string]any
+	if jsonErr := json.Unmarshal(buf.Bytes(), &payload); jsonErr != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), jsonErr)
+	}
+	if payload["error"] != "startup: empty" {
+		t.Fatalf("errors must stay strings unless rendering is enabled, got %v", payload["error"])
+	}
+}
+
+type contactError struct{}
+
+func (contactError) Error() string { return "contact failed" }
+
+func (contactError) LogValue() Value {
+	return GroupValue(String("email", "alice.smith@example.com"), String("phone", "13812345678"))
+}
+
+func TestDLPResolvesLogValuerErrorsWhenRenderingOff(t *testing.T) {
+	resetForTest()
+	SetErrorRenderOptions(ErrorRenderOptions{})
+	EnableDLPLogger()
+	defer DisableDLPLogger()
+
+	var buf bytes.Buffer
+	NewLogger(&buf, true, false).Error("notify", "err", contactError{})
+
+	out := buf.String()
+	if strings.Contains(out, "alice.smith@example.com") || strings.Contains(out, "13812345678") {
+		t.Fatalf("LogValuer error must be resolved before DLP: %q", out)
+	}
+	if !strings.Contains(out, "138****5678") {
+		t.Fatalf("expected masked phone, got %q", out)
+	}
+}
@@ -0,0 +1,291 @@
+package slog
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+const (
+	defaultFlightRecorderSize    = 100
+	defaultFlightRecorderMaxKeys = 1024
+	defaultFlightRecorderMarker  = "flight_recorder"
+)
+
+// FlightRecorderOptions 配置飞行记录器。
+type FlightRecorderOptions struct {
+	// Size 每个缓冲键保留的最近记录数，默认 100。
+	Size int
+	// Level 直接输出的最低级别，低于该级别的记录进入缓冲；为 nil 时沿用下游 handler 的 Enabled 判断。
+	Level Leveler
+	// CaptureLevel 进入缓冲的最低级别，更低的记录直接丢弃且 Enabled 返回 false；为 nil 时缓冲全部级别。
+	CaptureLevel Leveler
+	// TriggerLevel 触发回放的级别，默认 LevelError。
+	TriggerLevel Leveler
+	// Key 从 WithValue 设置的 Fields 中读取缓冲键。
+	Key string
+	// ContextKey 从 ctx.Value 读取缓冲键，优先于 Key。
+	ContextKey any
+	// MaxKeys 同时保留的缓冲键上限，超出时淘汰最久未写入的键，默认 1024。
+	MaxKeys int
+	// MarkerKey 回放记录上追加的标记属性名，默认 "flight_recorder"。
+	MarkerKey string
+}
+
+// FlightRecorderHandler 在内存中按请求/上下文键保留被过滤的低级别记录，
+// 遇到触发级别的记录时先回放这些记录，再输出触发记录本身。
+type FlightRecorderHandler struct {
+	next  slog.Handler
+	state *flightRecorderState
+}
+
+type flightRecorderState struct {
+	opts    FlightRecorderOptions
+	mu      sync.Mutex
+	buffers map[string]*list.Element
+	order   *list.List // 元素为 *flightBuffer，越靠后越新
+}
+
+type flightBuffer struct {
+	key     string
+	entries []flightEntry
+	next    int
+	full    bool
+}
+
+type flightEntry struct {
+	handler slog.Handler
+	ctx     context.Context
+	record  slog.Record
+}
+
+// NewFlightRecorderHandler 使用 next 作为下游创建飞行记录器。
+func NewFlightRecorderHandler(next Handler, opts FlightRecorderOptions) *FlightRecorderHandler {
+	if next == nil {
+		next = DiscardHandler
+	}
+	if opts.Size <= 0 {
+		opts.Size = defaultFlightRecorderSize
+	}
+	if opts.TriggerLevel == nil {
+		opts.TriggerLevel = LevelError
+	}
+	if opts.MaxKeys <= 0 {
+		opts.MaxKeys = defaultFlightRecorderMaxKeys
+	}
+	if opts.MarkerKey == "" {
+		opts.MarkerKey = defaultFlightRecorderMarker
+	}
+	return &FlightRecorderHandler{
+		next: next,
+		state: &flightRecorderState{
+			opts:    opts,
+			buffers: make(map[string]*list.Element),
+			order:   list.New(),
+		},
+	}
+}
+
+// Enabled 对不低于 CaptureLevel 的级别返回 true：被过滤的级别也需要进入缓冲。
+// 低于 CaptureLevel 的级别仅在下游直接输出时启用。
+func (h *FlightRecorderHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.state.captures(level) || h.passThrough(ctx, level)
+}
+
+// Handle 缓冲低级别记录，或在触发级别时先回放缓冲再输出当前记录。
+func (h *FlightRecorderHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opts := &h.state.opts
+	if r.Level >= opts.TriggerLevel.Level() {
+		replayErr := h.state.flush(h.state.key(ctx))
+		return errors.Join(replayErr, h.next.Handle(ctx, r))
+	}
+	if h.passThrough(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	if !h.state.captures(r.Level) {
+		return nil
+	}
+	h.state.push(h.state.key(ctx), flightEntry{handler: h.next, ctx: ctx, record: r.Clone()})
+	return nil
+}
+
+// WithAttrs 返回共享同一缓冲区的新 handler。
+func (h *FlightRecorderHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &FlightRecorderHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup 返回共享同一缓冲区的新 handler。
+func (h *FlightRecorderHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &FlightRecorderHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Flush 立即回放 ctx 对应缓冲键的记录。
+func (h *FlightRecorderHandler) Flush(ctx context.Context) error {
+	return h.state.flush(h.state.key(ctx))
+}
+
+// Release 丢弃 ctx 对应缓冲键的记录，通常在请求正常结束时调用。
+func (h *FlightRecorderHandler) Release(ctx context.Context) {
+	h.state.take(h.state.key(ctx))
+}
+
+// Buffered 返回 ctx 对应缓冲键当前保留的记录数。
+func (h *FlightRecorderHandler) Buffered(ctx context.Context) int {
+	key := h.state.key(ctx)
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	if elem, ok := h.state.buffers[key]; ok {
+		return len(elem.Value.(*flightBuffer).entries)
+	}
+	return 0
+}
+
+func (h *FlightRecorderHandler) passThrough(ctx context.Context, level slog.Level) bool {
+	if lvl := h.state.opts.Level; lvl != nil {
+		return level >= lvl.Level() || levelOverrideEnables(ctx, level)
+	}
+	return h.next.Enabled(ctx, level)
+}
+
+// captures 判断级别是否需要进入缓冲。
+func (s *flightRecorderState) captures(level slog.Level) bool {
+	return s.opts.CaptureLevel == nil || level >= s.opts.CaptureLevel.Level()
+}
+
+// key 解析缓冲键；未配置或上下文中缺失时回落到全局缓冲（空键）。
+func (s *flightRecorderState) key(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if s.opts.ContextKey != nil {
+		if v := ctx.Value(s.opts.ContextKey); v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	if s.opts.Key != "" {
+		if fields := getFields(ctx); fields != nil {
+			fields.mu.RLock()
+			v, ok := fields.values[s.opts.Key]
+			fields.mu.RUnlock()
+			if ok {
+				return fmt.Sprint(v)
+			}
+		}
+	}
+	return ""
+}
+
+func (s *flightRecorderState) push(key string, entry flightEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.buffers[key]
+	if ok {
+		s.order.MoveToBack(elem)
+	} else {
+		for len(s.buffers) >= s.opts.MaxKeys {
+			oldest := s.order.Front()
+			delete(s.buffers, oldest.Value.(*flightBuffer).key)
+			s.order.Remove(oldest)
+		}
+		elem = s.order.PushBack(&flightBuffer{key: key, entries: make([]flightEntry, 0, min(s.opts.Size, 16))})
+		s.buffers[key] = elem
+	}
+	elem.Value.(*flightBuffer).add(entry, s.opts.Size)
+}
+
+func (s *flightRecorderState) take(key string) []flightEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.buffers[key]
+	if !ok {
+		return nil
+	}
+	delete(s.buffers, key)
+	s.order.Remove(elem)
+	return elem.Value.(*flightBuffer).ordered()
+}
+
+func (s *flightRecorderState) flush(key string) error {
+	entries := s.take(key)
+	if len(entries) == 0 {
+		return nil
+	}
+	marker := slog.Bool(s.opts.MarkerKey, true)
+	var errs []error
+	for _, entry := range entries {
+		entry.record.AddAttrs(marker)
+		if err := entry.handler.Handle(entry.ctx, entry.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *flightBuffer) add(entry flightEntry, size int) {
+	if len(b.entries) < size {
+		b.entries = append(b.entries, entry)
+		return
+	}
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % size
+	b.full = true
+}
+
+// ordered 按写入顺序返回缓冲记录。
+func (b *flightBuffer) ordered() []flightEntry {
+	if !b.full {
+		return b.entries
+	}
+	out := make([]flightEntry, 0, len(b.entries))
+	out = append(out, b.entries[b.next:]...)
+	return append(out, b.entries[:b.next]...)
+}
+
+// WithFlightRecorder 返回为文本/JSON 输出挂载飞行记录器的新 Logger。
+// 每个输出各自维护缓冲区，回放记录沿原输出链路写出。
+func (l *Logger) WithFlightRecorder(opts FlightRecorderOptions) *Logger {
+	if l == nil {
+		return nil
+	}
+	newLogger := l.clone()
+	wrap := func(next slog.Handler) slog.Handler {
+		return NewFlightRecorderHandler(next, opts)
+	}
+	if newLogger.text != nil {
+		newLogger.text = slog.New(wrapInnerHandler(newLogger.text.Handler(), wrap))
+	}
+	if newLogger.json != nil {
+		newLogger.json = slog.New(wrapInnerHandler(newLogger.json.Handler(), wrap))
+	}
+	return newLogger
+}
+
+// wrapInnerHandler 将 wrap 应用到 eHandler 的下游，保证上下文字段、DLP 与格式化器已作用于被缓冲的记录。
+func wrapInnerHandler(handler slog.Handler, wrap func(slog.Handler) slog.Handler) slog.Handler {
+	eh, ok := handler.(*eHandler)
+	if !ok {
+		return wrap(handler)
+	}
+	return &eHandler{
+		handler:     wrap(eh.handler),
+		opts:        eh.opts,
+		groups:      slices.Clone(eh.groups),
+		prefixes:    slices.Clone(eh.prefixes),
+		observerOps: cloneObserverOperations(eh.observerOps),
+		ctx:         eh.ctx,
+	}
+}
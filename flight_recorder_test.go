@@ -0,0 +1,104 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFlightRecorderReplaysBufferedRecordsOnTrigger(t *testing.T) {
+	resetForTest()
+	SetLevelInfo()
+	defer resetForTest()
+
+	var buf bytes.Buffer
+	base := NewLogger(&buf, true, false).WithFlightRecorder(FlightRecorderOptions{Key: "request_id"})
+	reqA := base.WithValue("request_id", "a")
+	reqB := base.WithValue("request_id", "b")
+
+	reqA.Debug("a-step-1")
+	reqB.Debug("b-step-1")
+	reqA.Debug("a-step-2")
+	reqA.Info("a-info")
+	if out := buf.String(); strings.Contains(out, "step") || !strings.Contains(out, "a-info") {
+		t.Fatalf("expected only info to pass through before trigger, got:\n%s", out)
+	}
+
+	buf.Reset()
+	reqA.Error("a-failed")
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 replayed records plus trigger, got:\n%s", out)
+	}
+	if !strings.Contains(lines[0], "a-step-1") || !strings.Contains(lines[1], "a-step-2") || !strings.Contains(lines[2], "a-failed") {
+		t.Fatalf("unexpected replay order:\n%s", out)
+	}
+	if !strings.Contains(lines[0], "flight_recorder=true") || strings.Contains(lines[2], "flight_recorder") {
+		t.Fatalf("expected marker only on replayed records:\n%s", out)
+	}
+	if strings.Contains(out, "b-step-1") {
+		t.Fatalf("records from another key must not be replayed:\n%s", out)
+	}
+}
+
+func TestFlightRecorderHandlerRingAndRelease(t *testing.T) {
+	var buf bytes.Buffer
+	next := NewConsoleHandler(&buf, true, NewOptions(&HandlerOptions{Level: LevelInfo}))
+	type reqKey struct{}
+	h := NewFlightRecorderHandler(next, FlightRecorderOptions{Size: 2, ContextKey: reqKey{}, MarkerKey: "replayed"})
+	logger := New(h)
+
+	ctx := context.WithValue(context.Background(), reqKey{}, 42)
+	for _, msg := range []string{"d1", "d2", "d3"} {
+		logger.DebugContext(ctx, msg)
+	}
+	if n := h.Buffered(ctx); n != 2 {
+		t.Fatalf("expected ring buffer to keep 2 records, got %d", n)
+	}
+	if err := h.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "d1") || !strings.Contains(out, "d2") || !strings.Contains(out, "d3") || !strings.Contains(out, "replayed=true") {
+		t.Fatalf("unexpected flushed output:\n%s", out)
+	}
+
+	buf.Reset()
+	logger.DebugContext(ctx, "d4")
+	h.Release(ctx)
+	logger.ErrorContext(ctx, "boom")
+	if out := buf.String(); strings.Contains(out, "d4") || !strings.Contains(out, "boom") {
+		t.Fatalf("expected released records to be discarded:\n%s", out)
+	}
+}
+
+func TestFlightRecorderCaptureLevel(t *testing.T) {
+	var buf bytes.Buffer
+	next := NewConsoleHandler(&buf, true, NewOptions(&HandlerOptions{Level: LevelInfo}))
+	h := NewFlightRecorderHandler(next, FlightRecorderOptions{CaptureLevel: LevelDebug})
+	ctx := context.Background()
+
+	if h.Enabled(ctx, LevelTrace) {
+		t.Fatal("levels below CaptureLevel must be disabled")
+	}
+	if !h.Enabled(ctx, LevelDebug) || !h.Enabled(ctx, LevelInfo) {
+		t.Fatal("captured and pass-through levels must be enabled")
+	}
+
+	logger := New(h)
+	logger.Log(ctx, LevelTrace, "trace")
+	logger.Debug("debug")
+	if n := h.Buffered(ctx); n != 1 {
+		t.Fatalf("expected only the debug record to be buffered, got %d", n)
+	}
+	logger.Error("boom")
+	if out := buf.String(); strings.Contains(out, "trace") || !strings.Contains(out, "debug") {
+		t.Fatalf("unexpected replay:\n%s", out)
+	}
+
+	if !NewFlightRecorderHandler(next, FlightRecorderOptions{}).Enabled(ctx, LevelTrace) {
+		t.Fatal("without CaptureLevel every level is buffered")
+	}
+}
@@ -7,25 +7,17 @@ import (
 	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
 	"runtime"
 	"slices"
 	"strconv"
 	"sync"
+	"time"
 	"unicode"
 	"unicode/utf8"
 )
 
 var (
-	defaultLevel  = LevelInfo
-	levelColorMap = map[slog.Level]string{
-		LevelDebug: ansiBrightBlue,
-		LevelInfo:  ansiBrightGreen,
-		LevelWarn:  ansiBrightYellow,
-		LevelError: ansiBrightRed,
-		LevelTrace: ansiBrightPurple,
-		LevelFatal: ansiBrightRed,
-	}
+	defaultLevel = LevelInfo
 )
 
 type handler struct {
@@ -38,6 +30,12 @@ type handler struct {
 	replaceAttr        func(groups []string, a slog.Attr) slog.Attr
 	addSource, noColor bool
 	dynamicTTY         bool
+	theme              *Theme
+	pretty             *PrettyOptions // 非 nil 时启用多行输出
+	prettyAttrs        []prettyAttr   // pretty 模式下 WithAttrs 添加的属性
+	source             *SourceOptions // 源码位置格式，nil 时输出 [file:line]
+	hyperlinks         bool           // 写入交互式终端时输出 OSC-8 超链接
+	timeSettings       *timeSettings  // 非 nil 时由 handler 自行格式化记录时间
 }
 
 type handlerState struct {
@@ -89,11 +87,21 @@ func (s *groupState) values() []string {
 }
 
 // NewConsoleHandler returns a [log/slog.Handler] using the receiver's options.
-// Default options are used if opts is nil.
-func NewConsoleHandler(w io.Writer, noColor bool, opts *HandlerOptions) Handler {
+// Default options are used if opts is nil. noColor 为 false 时仍遵循 NO_COLOR / TERM=dumb，
+// 主题与着色模式通过 WithTheme、WithColorMode 等 ConsoleOption 调整。
+func NewConsoleHandler(w io.Writer, noColor bool, opts *HandlerOptions, options ...ConsoleOption) Handler {
 	if opts == nil {
 		opts = &slog.HandlerOptions{}
 	}
+	cfg := consoleConfig{theme: themeDefault}
+	if noColor {
+		cfg.color = ColorNever
+	}
+	for _, opt := range options {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
 	h := &handler{
 		w:           w,
 		state:       &handlerState{},
@@ -107,16 +115,57 @@ func NewConsoleHandler(w io.Writer, noColor bool, opts *HandlerOptions) Handler
 		h.level = defaultLevel
 	}
 	if h.timeFormat == "" {
-		h.timeFormat = TimeFormat
+		h.timeFormat = currentTimeFormat()
+	}
+
+	h.noColor = !colorEnabled(cfg.color, w)
+	h.theme = cfg.theme
+	h.pretty = cfg.pretty
+	h.source = cfg.source
+	h.hyperlinks = h.dynamicTTY && cfg.source != nil && cfg.source.Hyperlink != ""
+	if ts := cfg.time; ts != nil {
+		settings := *ts
+		settings.fallback = h.timeFormat
+		if settings.layout == "" {
+			settings.layout = h.timeFormat
+		}
+		if settings.relative && settings.start.IsZero() {
+			settings.start = time.Now()
+		}
+		h.timeFormat = settings.layout
+		h.timeSettings = &settings
 	}
-
-	h.noColor = noColor
 	return h
 }
 
+// formatTime 按实例时间设置格式化 t，未设置时使用 timeFormat。
+func (h *handler) formatTime(t time.Time) string {
+	if h.timeSettings != nil {
+		return h.timeSettings.format(t)
+	}
+	return t.Format(h.timeFormat)
+}
+
+// formattedTime 处理 ReplaceAttr 返回的时间字符串：若它只是通用格式化器按全局格式
+// 或本实例布局生成的时间（未被用户改写），仍按实例时间设置输出；其余情况原样使用。
+func (h *handler) formattedTime(t time.Time, s string) string {
+	ts := h.timeSettings
+	if ts == nil {
+		return s
+	}
+	abs := t
+	if ts.location != nil {
+		abs = abs.In(ts.location)
+	}
+	if s == abs.Format(ts.layout) || s == t.Format(ts.fallback) {
+		return ts.format(t)
+	}
+	return s
+}
+
 // Enabled indicates whether the receiver logs at the given level.
-func (h *handler) Enabled(_ context.Context, l slog.Level) bool {
-	return l.Level() >= h.level.Level()
+func (h *handler) Enabled(ctx context.Context, l slog.Level) bool {
+	return l.Level() >= h.level.Level() || levelOverrideEnables(ctx, l)
 }
 
 // Handle formats a given record in a human-friendly but still largely structured way.
@@ -129,15 +178,15 @@ func (h *handler) Handle(ctx context.Context, r slog.Record) error {
 	groups := h.groupPrefix.clone()
 
 	if !r.Time.IsZero() {
-		val := r.Time.Round(0)
 		if rep == nil {
-			t := r.Time
-			sb.AppendString(t.Format(h.timeFormat))
-		} else if a := rep(nil, slog.Time(slog.TimeKey, val)); a.Key != "" {
+			h.appendStyled(sb, h.styles().Time, h.formatTime(r.Time))
+			sb.AppendByte(' ')
+		} else if a := rep(nil, slog.Time(slog.TimeKey, r.Time.Round(0))); a.Key != "" {
+			// ReplaceAttr 始终收到 time.Time，格式、时区与相对时间在这里应用。
 			if a.Value.Kind() == slog.KindTime {
-				sb.AppendString(a.Value.Time().Format(h.timeFormat))
+				h.appendStyled(sb, h.styles().Time, h.formatTime(a.Value.Time()))
 			} else if a.Value.Kind() == slog.KindString {
-				sb.AppendString(a.Value.String())
+				h.appendStyled(sb, h.styles().Time, h.formattedTime(r.Time, a.Value.String()))
 			}
 			sb.AppendByte(' ')
 		}
@@ -147,21 +196,40 @@ func (h *handler) Handle(ctx context.Context, r slog.Record) error {
 	sb.AppendByte(' ')
 
 	if h.addSource && r.PC != 0 {
-		sb.AppendString(h.newSourceAttr(r.PC))
+		h.appendStyled(sb, h.styles().Source, h.newSourceAttr(r.PC))
 		sb.AppendByte(' ')
 	}
 
-	sb.AppendString(r.Message)
+	h.appendStyled(sb, h.styles().level(r.Level).Message, r.Message)
+
+	// 动态渲染需要单行输出，即使启用了 pretty 也回退到紧凑格式。
+	renderState, dynamic := dynamicRenderFromContext(ctx)
+	dynamic = dynamic && h.dynamicTTY
+	if h.pretty != nil && !dynamic {
+		sb.AppendByte('\n')
+		h.appendPrettyAttrs(sb, r)
+		return h.writeBuffer(sb)
+	}
+
 	if h.attrs != "" {
 		sb.AppendString(h.attrs)
 	}
+	var stack Stacktrace
 	r.Attrs(func(a slog.Attr) bool {
+		if rest, st, ok := splitStacktrace(a); ok {
+			stack = st
+			if rest.Key == "" {
+				return true
+			}
+			a = rest
+		}
 		h.appendAttr(sb, &groups, a)
 		return true
 	})
 	sb.AppendByte('\n')
+	h.appendStacktrace(sb, stack)
 
-	if renderState, ok := dynamicRenderFromContext(ctx); ok && h.dynamicTTY {
+	if dynamic {
 		return h.writeDynamicBuffer(sb, renderState.final)
 	}
 
@@ -184,6 +252,12 @@ func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		h2.appendAttr(sb, &state, a)
 	}
 	h2.attrs += sb.String()
+	if h2.pretty != nil {
+		groups := slices.Clone(h2.groupPrefix.values())
+		for _, a := range attrs {
+			h2.prettyAttrs = append(h2.prettyAttrs, prettyAttr{groups: groups, attr: a})
+		}
+	}
 	return h2
 }
 
@@ -199,16 +273,22 @@ func (h *handler) WithGroup(name string) slog.Handler {
 
 func (h *handler) clone() *handler {
 	return &handler{
-		w:           h.w,
-		state:       h.state,
-		level:       h.level,
-		groupPrefix: h.groupPrefix.clone(),
-		attrs:       h.attrs,
-		timeFormat:  h.timeFormat,
-		replaceAttr: h.replaceAttr,
-		addSource:   h.addSource,
-		noColor:     h.noColor,
-		dynamicTTY:  h.dynamicTTY,
+		w:            h.w,
+		state:        h.state,
+		level:        h.level,
+		groupPrefix:  h.groupPrefix.clone(),
+		attrs:        h.attrs,
+		timeFormat:   h.timeFormat,
+		replaceAttr:  h.replaceAttr,
+		addSource:    h.addSource,
+		noColor:      h.noColor,
+		dynamicTTY:   h.dynamicTTY,
+		theme:        h.theme,
+		pretty:       h.pretty,
+		prettyAttrs:  slices.Clip(h.prettyAttrs),
+		source:       h.source,
+		hyperlinks:   h.hyperlinks,
+		timeSettings: h.timeSettings,
 	}
 }
 
@@ -280,23 +360,23 @@ func isInteractiveWriter(w io.Writer) bool {
 }
 
 func (h *handler) appendLevel(sb *buffer, level slog.Level) {
-	color, ok := levelColorMap[level]
-	if !ok {
-		color = ansiBrightRed
-	}
-
-	sb.AppendStringIf(!h.noColor, color)
-	sb.AppendString("[")
-	sb.AppendString(levelTextName(level))
-	sb.AppendString("]")
-	sb.AppendStringIf(!h.noColor, ansiReset)
+	h.appendStyled(sb, h.styles().level(level).Label, "["+levelTextName(level)+"]")
 }
 
-func levelTextName(level slog.Level) string {
-	if name, ok := levelTextNames[level]; ok {
-		return name
+// styles 返回当前主题，直接构造的 handler 未设置主题时使用默认主题。
+func (h *handler) styles() *Theme {
+	if h.theme == nil {
+		return themeDefault
 	}
-	return level.String()
+	return h.theme
+}
+
+// appendStyled 以 style 着色写入 s，style 为空或禁用颜色时原样写入。
+func (h *handler) appendStyled(sb *buffer, style, s string) {
+	colored := !h.noColor && style != ""
+	sb.AppendStringIf(colored, style)
+	sb.AppendString(s)
+	sb.AppendStringIf(colored, ansiReset)
 }
 
 func (h *handler) appendAttr(sb *buffer, groups *groupState, a slog.Attr) {
@@ -323,13 +403,16 @@ func (h *handler) appendAttr(sb *buffer, groups *groupState, a slog.Attr) {
 		a = h.replaceAttr(groups.values(), a)
 	}
 	if !a.Equal(slog.Attr{}) {
-		appendKey(sb, groups.prefix(), a.Key)
-		h.appendVal(sb, a.Value)
+		h.appendKey(sb, groups.prefix(), a.Key)
+		h.appendStyledVal(sb, a.Value)
 	}
 }
 
-func appendKey(sb *buffer, prefix string, key string) {
+func (h *handler) appendKey(sb *buffer, prefix string, key string) {
 	sb.AppendByte(' ')
+	colored := !h.noColor && h.styles().Key != ""
+	sb.AppendStringIf(colored, h.styles().Key)
+	defer sb.AppendStringIf(colored, ansiReset)
 	if prefix != "" {
 		if key != "" {
 			key = prefix + "." + key
@@ -345,6 +428,17 @@ func appendKey(sb *buffer, prefix string, key string) {
 	sb.AppendByte('=')
 }
 
+// appendStyledVal 以主题的 Value 样式写入值，级别值保留自身的标签样式。
+func (h *handler) appendStyledVal(sb *buffer, val slog.Value) {
+	if _, isLevel := val.Any().(slog.Level); isLevel || h.noColor || h.styles().Value == "" {
+		h.appendVal(sb, val)
+		return
+	}
+	sb.AppendString(h.styles().Value)
+	h.appendVal(sb, val)
+	sb.AppendString(ansiReset)
+}
+
 func (h *handler) appendVal(sb *buffer, val slog.Value) {
 	switch val.Kind() {
 	case slog.KindString:
@@ -364,7 +458,11 @@ func (h *handler) appendVal(sb *buffer, val slog.Value) {
 		if quoteTime {
 			sb.AppendByte(' ')
 		}
-		sb.AppendString(val.Time().Format(h.timeFormat))
+		t := val.Time()
+		if h.timeSettings != nil && h.timeSettings.location != nil {
+			t = t.In(h.timeSettings.location)
+		}
+		sb.AppendString(t.Format(h.timeFormat))
 		if quoteTime {
 			sb.AppendByte(' ')
 		}
@@ -400,11 +498,6 @@ func appendString(sb *buffer, s string) {
 	}
 }
 
-func (h *handler) newSourceAttr(pc uintptr) string {
-	source := frame(pc)
-	return fmt.Sprintf("[%s:%d]", filepath.Base(source.File), source.Line)
-}
-
 func frame(pc uintptr) runtime.Frame {
 	fs := runtime.CallersFrames([]uintptr{pc})
 	f, _ := fs.Next()
@@ -72,6 +72,34 @@ func TestConsoleHandler_DefaultInfoAndCustomLevel(t *testing.T) {
 	}
 }
 
+// TestConsoleHandler_TimeSeparatedFromLevel 时间与级别标签之间必须有空格（无 ReplaceAttr 时曾缺失）。
+func TestConsoleHandler_TimeSeparatedFromLevel(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	keep := func(_ []string, a stdslog.Attr) stdslog.Attr { return a }
+	cases := []struct {
+		name    string
+		opts    *HandlerOptions
+		options []ConsoleOption
+	}{
+		{name: "plain", opts: nil},
+		{name: "replace attr", opts: &HandlerOptions{ReplaceAttr: keep}},
+		{name: "time settings", options: []ConsoleOption{WithTimeFormat(time.DateTime)}},
+		{name: "time settings with replace attr", opts: &HandlerOptions{ReplaceAttr: keep}, options: []ConsoleOption{WithTimeFormat(time.DateTime)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := NewConsoleHandler(&buf, true, tc.opts, tc.options...)
+			if err := h.Handle(context.Background(), stdslog.NewRecord(at, LevelInfo, "msg", 0)); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+			if out := buf.String(); !strings.Contains(out, " [I] msg") || strings.Contains(out, "[I]  ") {
+				t.Fatalf("time and level must be separated by one space, got %q", out)
+			}
+		})
+	}
+}
+
 // TestGlobalFunctionsCoverage 全局函数覆盖率测试
 func TestGlobalFunctionsCoverage(t *testing.T) {
 	// 测试级别设置函数
@@ -0,0 +1,168 @@
+package common
+
+import (
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+)
+
+// DuplicateKeyPolicy 决定同一层级出现重复属性键时的处理方式。
+type DuplicateKeyPolicy uint32
+
+const (
+	// DuplicateKeysKeepAll 不额外去重，保持原有输出（默认）。
+	DuplicateKeysKeepAll DuplicateKeyPolicy = iota
+	// DuplicateKeysLastWins 保留首次出现的位置，值取最后一次。
+	DuplicateKeysLastWins
+	// DuplicateKeysFirstWins 保留首次出现的键值，丢弃后续重复。
+	DuplicateKeysFirstWins
+	// DuplicateKeysSuffix 为后续重复键追加序号后缀，如 user、user#2、user#3。
+	DuplicateKeysSuffix
+)
+
+// String 返回策略名称。
+func (p DuplicateKeyPolicy) String() string {
+	switch p {
+	case DuplicateKeysKeepAll:
+		return "keep_all"
+	case DuplicateKeysLastWins:
+		return "last_wins"
+	case DuplicateKeysFirstWins:
+		return "first_wins"
+	case DuplicateKeysSuffix:
+		return "suffix"
+	default:
+		return "unknown"
+	}
+}
+
+var duplicateKeyPolicy atomic.Uint32
+
+// SetDuplicateKeyPolicy 设置全局重复键策略。
+func SetDuplicateKeyPolicy(p DuplicateKeyPolicy) {
+	duplicateKeyPolicy.Store(uint32(p))
+}
+
+// CurrentDuplicateKeyPolicy 返回当前全局重复键策略。
+func CurrentDuplicateKeyPolicy() DuplicateKeyPolicy {
+	return DuplicateKeyPolicy(duplicateKeyPolicy.Load())
+}
+
+// ApplyDuplicateKeyPolicy 按当前全局策略处理 attrs 顶层的重复键，recursive 为 true 时同时处理分组内部。
+// 无重复键时直接返回原切片。
+func ApplyDuplicateKeyPolicy(attrs []slog.Attr, recursive bool) []slog.Attr {
+	return CurrentDuplicateKeyPolicy().Apply(attrs, recursive)
+}
+
+// Apply 按策略 p 处理 attrs 的重复键，DuplicateKeysKeepAll 原样返回。
+func (p DuplicateKeyPolicy) Apply(attrs []slog.Attr, recursive bool) []slog.Attr {
+	if p == DuplicateKeysKeepAll {
+		return attrs
+	}
+	return applyDuplicateKeyPolicy(p, attrs, recursive, 0)
+}
+
+func applyDuplicateKeyPolicy(policy DuplicateKeyPolicy, attrs []slog.Attr, recursive bool, depth int) []slog.Attr {
+	if recursive && depth <= maxRecursionDepth {
+		var cloned bool
+		for i, attr := range attrs {
+			if attr.Value.Kind() != slog.KindGroup {
+				continue
+			}
+			group := attr.Value.Group()
+			next := applyDuplicateKeyPolicy(policy, group, true, depth+1)
+			if len(next) == len(group) && (len(next) == 0 || &next[0] == &group[0]) {
+				continue
+			}
+			if !cloned {
+				attrs = append([]slog.Attr(nil), attrs...)
+				cloned = true
+			}
+			attrs[i].Value = slog.GroupValue(next...)
+		}
+	}
+	if !hasDuplicateKeys(attrs) {
+		return attrs
+	}
+
+	switch policy {
+	case DuplicateKeysFirstWins:
+		out := make([]slog.Attr, 0, len(attrs))
+		seen := make(map[string]struct{}, len(attrs))
+		for _, attr := range attrs {
+			if _, ok := seen[attr.Key]; ok && attr.Key != "" {
+				continue
+			}
+			seen[attr.Key] = struct{}{}
+			out = append(out, attr)
+		}
+		return out
+	case DuplicateKeysSuffix:
+		out := make([]slog.Attr, len(attrs))
+		used := make(map[string]struct{}, len(attrs))
+		for _, attr := range attrs {
+			used[attr.Key] = struct{}{}
+		}
+		seen := make(map[string]struct{}, len(attrs))
+		for i, attr := range attrs {
+			if _, dup := seen[attr.Key]; dup && attr.Key != "" {
+				n := 2
+				for {
+					candidate := attr.Key + "#" + strconv.Itoa(n)
+					if _, taken := used[candidate]; !taken {
+						used[candidate] = struct{}{}
+						attr.Key = candidate
+						break
+					}
+					n++
+				}
+			}
+			seen[attr.Key] = struct{}{}
+			out[i] = attr
+		}
+		return out
+	default:
+		out := make([]slog.Attr, 0, len(attrs))
+		index := make(map[string]int, len(attrs))
+		for _, attr := range attrs {
+			if i, ok := index[attr.Key]; ok && attr.Key != "" {
+				out[i] = attr
+				continue
+			}
+			index[attr.Key] = len(out)
+			out = append(out, attr)
+		}
+		return out
+	}
+}
+
+// hasDuplicateKeys 判断是否存在重复键（空键表示内联分组，不参与比较），少量属性时避免分配 map。
+func hasDuplicateKeys(attrs []slog.Attr) bool {
+	if len(attrs) < 2 {
+		return false
+	}
+	if len(attrs) <= 8 {
+		for i := 1; i < len(attrs); i++ {
+			if attrs[i].Key == "" {
+				continue
+			}
+			for j := 0; j < i; j++ {
+				if attrs[i].Key == attrs[j].Key {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	seen := make(map[string]struct{}, len(attrs))
+	for _, attr := range attrs {
+		if attr.Key == "" {
+			continue
+		}
+		if _, ok := seen[attr.Key]; ok {
+			return true
+		}
+		seen[attr.Key] = struct{}{}
+	}
+	return false
+}
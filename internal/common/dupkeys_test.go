@@ -0,0 +1,63 @@
+package common
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func attrKeys(attrs []slog.Attr) string {
+	keys := make([]string, len(attrs))
+	for i, a := range attrs {
+		keys[i] = a.Key + "=" + a.Value.String()
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestApplyDuplicateKeyPolicy(t *testing.T) {
+	defer SetDuplicateKeyPolicy(DuplicateKeysKeepAll)
+	attrs := []slog.Attr{
+		slog.Int("user", 1), slog.Int("user#2", 9), slog.Int("id", 5), slog.Int("user", 2), slog.Int("user", 3),
+	}
+	cases := []struct {
+		policy DuplicateKeyPolicy
+		want   string
+	}{
+		{DuplicateKeysLastWins, "user=3,user#2=9,id=5"},
+		{DuplicateKeysKeepAll, "user=1,user#2=9,id=5,user=2,user=3"},
+		{DuplicateKeysFirstWins, "user=1,user#2=9,id=5"},
+		{DuplicateKeysSuffix, "user=1,user#2=9,id=5,user#3=2,user#4=3"},
+	}
+	for _, tc := range cases {
+		SetDuplicateKeyPolicy(tc.policy)
+		if got := attrKeys(ApplyDuplicateKeyPolicy(attrs, false)); got != tc.want {
+			t.Fatalf("%s: got %s, want %s", tc.policy, got, tc.want)
+		}
+	}
+}
+
+func TestDuplicateKeyPolicyZeroValueKeepsAll(t *testing.T) {
+	var policy DuplicateKeyPolicy
+	if policy != DuplicateKeysKeepAll || CurrentDuplicateKeyPolicy() != DuplicateKeysKeepAll {
+		t.Fatalf("zero value should keep all keys, got %s", policy)
+	}
+	// 默认策略下模块 WithAttrs 仍按 UniqAttrs 去重。
+	attrs := AppendAttrsToGroup(nil, []slog.Attr{slog.String("user", "a")}, slog.String("user", "b"))
+	if got := attrKeys(attrs); got != "user=b" {
+		t.Fatalf("unexpected attrs: %s", got)
+	}
+}
+
+func TestAppendAttrsToGroupPolicy(t *testing.T) {
+	defer SetDuplicateKeyPolicy(DuplicateKeysKeepAll)
+	SetDuplicateKeyPolicy(DuplicateKeysSuffix)
+
+	attrs := AppendAttrsToGroup([]string{"req"}, nil, slog.String("user", "a"))
+	attrs = AppendAttrsToGroup([]string{"req"}, attrs, slog.String("user", "b"), slog.String("", "inline"))
+	if len(attrs) != 1 || attrs[0].Key != "req" {
+		t.Fatalf("unexpected attrs: %v", attrs)
+	}
+	if got := attrKeys(attrs[0].Value.Group()); got != "user=a,user#2=b,=inline" {
+		t.Fatalf("unexpected group attrs: %s", got)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"slices"
 )
 
+// AppendAttrsToGroup 将 newAttrs 追加到 groups 指定的分组内并处理同层重复键：
+// 全局策略为默认的 DuplicateKeysKeepAll 时沿用 UniqAttrs（后者覆盖），否则按全局策略处理。
 func AppendAttrsToGroup(groups []string, actualAttrs []slog.Attr, newAttrs ...slog.Attr) []slog.Attr {
 	actualAttrs = slices.Clone(actualAttrs)
 
 	if len(groups) == 0 {
-		return UniqAttrs(append(actualAttrs, newAttrs...))
+		return dedupeGroupAttrs(append(actualAttrs, newAttrs...))
 	}
 
 	for i := range actualAttrs {
@@ -20,7 +22,7 @@ func AppendAttrsToGroup(groups []string, actualAttrs []slog.Attr, newAttrs ...sl
 		}
 	}
 
-	return UniqAttrs(
+	return dedupeGroupAttrs(
 		append(
 			actualAttrs,
 			slog.Group(
@@ -31,6 +33,13 @@ func AppendAttrsToGroup(groups []string, actualAttrs []slog.Attr, newAttrs ...sl
 	)
 }
 
+func dedupeGroupAttrs(attrs []slog.Attr) []slog.Attr {
+	if policy := CurrentDuplicateKeyPolicy(); policy != DuplicateKeysKeepAll {
+		return policy.Apply(attrs, true)
+	}
+	return UniqAttrs(attrs)
+}
+
 func UniqAttrs(attrs []slog.Attr) []slog.Attr {
 	return uniqAttrsWithDepth(attrs, 0)
 }
@@ -0,0 +1,399 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 内置的 JSON 字段映射配置名，用于 JSONSchema.Profile。
+const (
+	// JSONProfileECS 输出 Elastic Common Schema：@timestamp、log.level、message、error.*、http.*、url.*、log.origin.*。
+	JSONProfileECS = "ecs"
+	// JSONProfileOTel 输出 OpenTelemetry 日志模型：timestamp、severity_text、severity_number、body，
+	// 用户属性收拢到 attributes 下并使用语义约定键名。
+	JSONProfileOTel = "otel"
+)
+
+// ECSVersion 是 ecs 配置写入 ecs.version 的版本号。
+const ECSVersion = "8.11.0"
+
+// profileFields 是字段映射配置使用的语义键名。
+type profileFields struct {
+	errMessage, errType, errStack, errCause                       string
+	reqMethod, reqHeader                                          string
+	urlFull, urlScheme, urlDomain, urlPath, urlQuery, urlFragment string
+	resStatus, resBodySize, resHeader                             string
+	srcFile, srcLine, srcFunction                                 string
+}
+
+// jsonProfile 描述一个字段映射配置：内置字段的默认 schema，以及用户属性的语义映射。
+type jsonProfile struct {
+	schema   JSONSchema
+	level    func(Level) slog.Attr // 非 nil 时替代 LevelKey/LevelStyle 输出级别
+	attrsKey string                // 非空时用户属性收拢到该分组，源码位置作为属性写入
+	static   []slog.Attr
+	fields   profileFields
+}
+
+var jsonProfiles = map[string]*jsonProfile{
+	JSONProfileECS: {
+		schema: JSONSchema{
+			TimeKey:    "@timestamp",
+			LevelKey:   "log.level",
+			MessageKey: "message",
+			LevelStyle: LevelStyleLower,
+			TimeLayout: "2006-01-02T15:04:05.000Z07:00",
+			UTC:        true,
+		},
+		static: []slog.Attr{slog.String("ecs.version", ECSVersion)},
+		fields: profileFields{
+			errMessage: "error.message", errType: "error.type", errStack: "error.stack_trace", errCause: "error.cause",
+			reqMethod: "http.request.method", reqHeader: "http.request.headers",
+			urlFull: "url.full", urlScheme: "url.scheme", urlDomain: "url.domain",
+			urlPath: "url.path", urlQuery: "url.query", urlFragment: "url.fragment",
+			resStatus: "http.response.status_code", resBodySize: "http.response.body.bytes",
+			resHeader: "http.response.headers",
+			srcFile:   "log.origin.file.name", srcLine: "log.origin.file.line", srcFunction: "log.origin.function",
+		},
+	},
+	JSONProfileOTel: {
+		schema: JSONSchema{
+			TimeKey:    "timestamp",
+			MessageKey: "body",
+			TimeLayout: time.RFC3339Nano,
+			UTC:        true,
+		},
+		level:    otelSeverity,
+		attrsKey: "attributes",
+		fields: profileFields{
+			errMessage: "exception.message", errType: "exception.type", errStack: "exception.stacktrace", errCause: "exception.cause",
+			reqMethod: "http.request.method", reqHeader: "http.request.header",
+			urlFull: "url.full", urlScheme: "url.scheme", urlDomain: "server.address",
+			urlPath: "url.path", urlQuery: "url.query", urlFragment: "url.fragment",
+			resStatus: "http.response.status_code", resBodySize: "http.response.body.size",
+			resHeader: "http.response.header",
+			srcFile:   "code.filepath", srcLine: "code.lineno", srcFunction: "code.function",
+		},
+	},
+}
+
+// JSONProfiles 返回内置字段映射配置名，按字母排序。
+func JSONProfiles() []string {
+	names := make([]string, 0, len(jsonProfiles))
+	for name := range jsonProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupJSONProfile(name string) (*jsonProfile, error) {
+	if p, ok := jsonProfiles[strings.ToLower(name)]; ok {
+		return p, nil
+	}
+	return nil, NewInvalidInputError("JSONSchema.Profile", strings.Join(JSONProfiles(), "|"), name)
+}
+
+// otelSeverity 按 OpenTelemetry 日志模型输出 severity_text 与 severity_number（TRACE=1 … FATAL=21）。
+func otelSeverity(level Level) slog.Attr {
+	var text string
+	var base, floor Level
+	switch {
+	case level >= LevelFatal:
+		text, base, floor = "FATAL", 21, LevelFatal
+	case level >= LevelError:
+		text, base, floor = "ERROR", 17, LevelError
+	case level >= LevelWarn:
+		text, base, floor = "WARN", 13, LevelWarn
+	case level >= LevelInfo:
+		text, base, floor = "INFO", 9, LevelInfo
+	case level >= LevelDebug:
+		text, base, floor = "DEBUG", 5, LevelDebug
+	default:
+		text, base, floor = "TRACE", 1, LevelTrace
+	}
+	// 同一区间内的自定义级别映射到 +1…+3 的细分值。
+	offset := min(max(level-floor, 0), 3)
+	return slog.Attr{Value: slog.GroupValue(
+		slog.String("severity_text", text),
+		slog.Int("severity_number", int(base+offset)),
+	)}
+}
+
+// profileHandler 位于 JSON handler 之前，把 HTTP / 错误等属性映射为配置中的语义键名。
+// WithAttrs / WithGroup 在本层累积，Handle 时组装为完整的属性树，以便整体收拢到 attrsKey 下。
+type profileHandler struct {
+	next      slog.Handler
+	profile   *jsonProfile
+	addSource bool
+	attrs     []slog.Attr   // 顶层属性（已映射）
+	errMapped bool          // attrs 中已有错误占用了 error.* 键
+	groups    []string      // WithGroup 打开的分组
+	scoped    [][]slog.Attr // scoped[i] 为打开 groups[i] 之后添加的属性
+}
+
+func (h *profileHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *profileHandler) Handle(ctx context.Context, r slog.Record) error {
+	inner := make([]slog.Attr, 0, r.NumAttrs())
+	errMapped := h.errMapped
+	r.Attrs(func(a slog.Attr) bool {
+		if len(h.groups) == 0 {
+			inner = h.appendMapped(inner, a, &errMapped)
+		} else {
+			inner = append(inner, a)
+		}
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		children := append(slices.Clip(h.scoped[i]), inner...)
+		inner = nil
+		if len(children) > 0 {
+			inner = []slog.Attr{{Key: h.groups[i], Value: slog.GroupValue(children...)}}
+		}
+	}
+
+	attrs := make([]slog.Attr, 0, len(h.attrs)+len(inner)+3)
+	if h.profile.attrsKey != "" && h.addSource && r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		attrs = append(attrs,
+			slog.String(h.profile.fields.srcFile, f.File),
+			slog.Int(h.profile.fields.srcLine, f.Line),
+			slog.String(h.profile.fields.srcFunction, f.Function),
+		)
+	}
+	attrs = append(append(attrs, h.attrs...), inner...)
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	switch {
+	case len(attrs) == 0:
+	case h.profile.attrsKey != "":
+		nr.AddAttrs(slog.Attr{Key: h.profile.attrsKey, Value: slog.GroupValue(attrs...)})
+	default:
+		nr.AddAttrs(attrs...)
+	}
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *profileHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := h.clone()
+	if n := len(clone.groups); n > 0 {
+		clone.scoped[n-1] = append(slices.Clip(clone.scoped[n-1]), attrs...)
+		return clone
+	}
+	for _, a := range attrs {
+		clone.attrs = h.appendMapped(clone.attrs, a, &clone.errMapped)
+	}
+	return clone
+}
+
+func (h *profileHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := h.clone()
+	clone.groups = append(clone.groups, name)
+	clone.scoped = append(clone.scoped, nil)
+	return clone
+}
+
+func (h *profileHandler) clone() *profileHandler {
+	return &profileHandler{
+		next:      h.next,
+		profile:   h.profile,
+		addSource: h.addSource,
+		attrs:     slices.Clip(h.attrs),
+		errMapped: h.errMapped,
+		groups:    slices.Clip(h.groups),
+		scoped:    slices.Clone(h.scoped),
+	}
+}
+
+// appendMapped 识别错误、调用栈以及 formatter.HTTPRequestFormatter / HTTPResponseFormatter /
+// ErrorFormatter 生成的分组，展开为扁平的语义键；其余属性原样追加。
+// 第一个错误占用 error.* 键并置 errMapped，之后的错误保留原键名，写成 {message,type,cause} 分组。
+func (h *profileHandler) appendMapped(dst []slog.Attr, a slog.Attr, errMapped *bool) []slog.Attr {
+	f := &h.profile.fields
+	if ev, ok := a.Value.Any().(*errorValue); ok && a.Value.Kind() == slog.KindAny {
+		return h.appendError(dst, a.Key, ev.Message, ev.Type, errorCauses(ev), errMapped)
+	}
+	if err, ok := errorFromValue(a.Value); ok {
+		return h.appendError(dst, a.Key, err.Error(), fmt.Sprintf("%T", err), nil, errMapped)
+	}
+	if st, ok := a.Value.Any().(Stacktrace); ok && a.Value.Kind() == slog.KindAny {
+		return append(dst, slog.String(f.errStack, stacktraceText(st)))
+	}
+
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return append(dst, a)
+	}
+	group := v.Group()
+	index := make(map[string]slog.Value, len(group))
+	for _, child := range group {
+		if _, dup := index[child.Key]; !dup {
+			index[child.Key] = child.Value
+		}
+	}
+	has := func(keys ...string) bool {
+		for _, k := range keys {
+			if _, ok := index[k]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case len(group) == 3 && has("message", "type", "stacktrace"):
+		if *errMapped {
+			return append(dst, a)
+		}
+		*errMapped = true
+		return append(dst,
+			slog.Attr{Key: f.errMessage, Value: index["message"]},
+			slog.Attr{Key: f.errType, Value: index["type"]},
+			slog.Attr{Key: f.errStack, Value: index["stacktrace"]},
+		)
+	case has("host", "method", "url", "headers"):
+		dst = append(dst, slog.Attr{Key: f.reqMethod, Value: index["method"]})
+		for _, child := range group {
+			if child.Key == "url" && child.Value.Kind() == slog.KindGroup {
+				dst = appendURLFields(dst, f, child.Value.Group(), index["host"])
+			}
+		}
+		return appendHeaderFields(dst, f.reqHeader, index["headers"])
+	case has("status", "status_text", "content_length"):
+		dst = append(dst,
+			slog.Attr{Key: f.resStatus, Value: index["status"]},
+			slog.Attr{Key: f.resBodySize, Value: index["content_length"]},
+		)
+		return appendHeaderFields(dst, f.resHeader, index["headers"])
+	}
+	return append(dst, a)
+}
+
+func (h *profileHandler) appendError(dst []slog.Attr, key, msg, typ string, causes []errorCause, errMapped *bool) []slog.Attr {
+	f := &h.profile.fields
+	if *errMapped {
+		group := []slog.Attr{slog.String("message", msg), slog.String("type", typ)}
+		if len(causes) > 0 {
+			group = append(group, slog.Any("cause", causes))
+		}
+		return append(dst, slog.Attr{Key: key, Value: slog.GroupValue(group...)})
+	}
+	*errMapped = true
+	dst = append(dst, slog.String(f.errMessage, msg), slog.String(f.errType, typ))
+	if len(causes) > 0 {
+		dst = append(dst, slog.Any(f.errCause, causes))
+	}
+	return dst
+}
+
+// errorCause 是错误链中的一环，按由外到内的顺序写入 error.cause。
+type errorCause struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// errorCauses 展开 errorValue 的 Cause 链与 errors.Join 的 Causes（深度优先）。
+func errorCauses(ev *errorValue) []errorCause {
+	var out []errorCause
+	var walk func(*errorValue)
+	walk = func(e *errorValue) {
+		if e.Cause != nil {
+			out = append(out, errorCause{Message: e.Cause.Message, Type: e.Cause.Type})
+			walk(e.Cause)
+		}
+		for _, c := range e.Causes {
+			if c != nil {
+				out = append(out, errorCause{Message: c.Message, Type: c.Type})
+				walk(c)
+			}
+		}
+	}
+	walk(ev)
+	return out
+}
+
+func appendURLFields(dst []slog.Attr, f *profileFields, url []slog.Attr, host slog.Value) []slog.Attr {
+	var domain slog.Value
+	for _, a := range url {
+		key := ""
+		switch a.Key {
+		case "url":
+			key = f.urlFull
+		case "scheme":
+			key = f.urlScheme
+		case "host":
+			domain = a.Value
+		case "path":
+			key = f.urlPath
+		case "raw_query":
+			key = f.urlQuery
+		case "fragment":
+			key = f.urlFragment
+		}
+		if key != "" && a.Value.String() != "" {
+			dst = append(dst, slog.Attr{Key: key, Value: a.Value})
+		}
+	}
+	// 服务端请求的 URL 通常不含 host，回退到 Request.Host。
+	if domain.String() == "" {
+		domain = host
+	}
+	if domain.String() != "" {
+		dst = append(dst, slog.Attr{Key: f.urlDomain, Value: domain})
+	}
+	return dst
+}
+
+// appendHeaderFields 把头部分组展开为 prefix.<小写头名>，被隐藏的头部（非分组）直接丢弃。
+func appendHeaderFields(dst []slog.Attr, prefix string, headers slog.Value) []slog.Attr {
+	if headers.Kind() != slog.KindGroup {
+		return dst
+	}
+	for _, h := range headers.Group() {
+		dst = append(dst, slog.Attr{Key: prefix + "." + strings.ToLower(h.Key), Value: h.Value})
+	}
+	return dst
+}
+
+// stacktraceText 以 Go panic 的格式输出调用栈：函数名一行，缩进的 file:line 一行。
+func stacktraceText(st Stacktrace) string {
+	var sb strings.Builder
+	for i, f := range st {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(f.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(f.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(f.Line))
+	}
+	return sb.String()
+}
+
+// profileSource 把源码位置映射为配置中的扁平字段，作为内联分组返回。
+func (p *jsonProfile) profileSource(src *slog.Source) slog.Attr {
+	return slog.Attr{Value: slog.GroupValue(
+		slog.String(p.fields.srcFile, filepath.Base(src.File)),
+		slog.Int(p.fields.srcLine, src.Line),
+		slog.String(p.fields.srcFunction, src.Function),
+	)}
+}
@@ -0,0 +1,133 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/darkit/slog/modules/formatter"
+)
+
+func TestJSONProfileECS(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.AddSource = true
+		c.JSONSchema = &JSONSchema{Profile: JSONProfileECS}
+	})
+
+	req := httptest.NewRequest("POST", "http://api.example.com/orders?id=7", nil)
+	req.Header.Set("User-Agent", "probe")
+	reqValue, _ := formatter.HTTPRequestFormatter(false)(nil, Any("request", req))
+	errValue, _ := formatter.ErrorFormatter("failure")(nil, Any("failure", errors.New("boom")))
+	logger.Error("request failed", "request", reqValue, "failure", errValue, "order", 7)
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"log.level":                       "error",
+		"message":                         "request failed",
+		"ecs.version":                     ECSVersion,
+		"http.request.method":             "POST",
+		"http.request.headers.user-agent": "probe",
+		"url.full":                        "http://api.example.com/orders?id=7",
+		"url.path":                        "/orders",
+		"url.query":                       "id=7",
+		"url.domain":                      "api.example.com",
+		"error.message":                   "boom",
+		"error.type":                      "*errors.errorString",
+		"log.origin.file.name":            "json_profile_test.go",
+		"order":                           float64(7),
+	}
+	for key, value := range want {
+		if payload[key] != value {
+			t.Fatalf("%s = %v, want %v: %s", key, payload[key], value, buf.String())
+		}
+	}
+	if ts, _ := payload["@timestamp"].(string); !strings.HasSuffix(ts, "Z") {
+		t.Fatalf("expected UTC @timestamp, got %q", ts)
+	}
+	for _, key := range []string{"request", "failure", "time", "level", "msg"} {
+		if _, ok := payload[key]; ok {
+			t.Fatalf("key %q should be mapped: %s", key, buf.String())
+		}
+	}
+}
+
+func TestJSONProfileOTel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.AddSource = true
+		c.JSONSchema = &JSONSchema{Profile: JSONProfileOTel}
+	})
+	logger.With("service", "billing").Warn("slow", "error", errors.New("timeout"))
+
+	var payload struct {
+		Timestamp      string         `json:"timestamp"`
+		SeverityText   string         `json:"severity_text"`
+		SeverityNumber int            `json:"severity_number"`
+		Body           string         `json:"body"`
+		Attributes     map[string]any `json:"attributes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if payload.SeverityText != "WARN" || payload.SeverityNumber != 13 || payload.Body != "slow" || payload.Timestamp == "" {
+		t.Fatalf("unexpected envelope: %s", buf.String())
+	}
+	attrs := payload.Attributes
+	if attrs["service"] != "billing" || attrs["exception.message"] != "timeout" {
+		t.Fatalf("unexpected attributes: %s", buf.String())
+	}
+	if file, _ := attrs["code.filepath"].(string); !strings.HasSuffix(file, "json_profile_test.go") {
+		t.Fatalf("expected source in attributes: %s", buf.String())
+	}
+}
+
+func TestJSONProfileHandlerGroups(t *testing.T) {
+	var buf bytes.Buffer
+	schema := &JSONSchema{Profile: JSONProfileOTel}
+	h := schema.newJSONHandler(&buf, &HandlerOptions{}, "", nil)
+	New(h).With("a", 1).WithGroup("g").With("b", 2).Info("m", "c", 3)
+
+	if !strings.Contains(buf.String(), `"attributes":{"a":1,"g":{"b":2,"c":3}}`) {
+		t.Fatalf("unexpected grouping: %s", buf.String())
+	}
+	if err := (&JSONSchema{Profile: "unknown"}).Validate(); err == nil {
+		t.Fatal("expected unknown profile error")
+	}
+}
+
+func TestJSONProfileMultipleErrorsAndCauses(t *testing.T) {
+	SetErrorRenderOptions(ErrorRenderOptions{Enabled: true})
+	defer SetErrorRenderOptions(ErrorRenderOptions{})
+
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.JSONSchema = &JSONSchema{Profile: JSONProfileECS}
+	})
+	root := errors.New("connection refused")
+	wrapped := fmt.Errorf("query users: %w", root)
+	logger.Error("failed", "err", wrapped, "cleanup_err", errors.New("close failed"))
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if payload["error.message"] != "query users: connection refused" {
+		t.Fatalf("first error should own error.message: %s", buf.String())
+	}
+	causes, _ := payload["error.cause"].([]any)
+	if len(causes) != 1 || causes[0].(map[string]any)["message"] != "connection refused" {
+		t.Fatalf("expected cause chain in error.cause: %s", buf.String())
+	}
+	cleanup, _ := payload["cleanup_err"].(map[string]any)
+	if cleanup["message"] != "close failed" || cleanup["type"] != "*errors.errorString" {
+		t.Fatalf("second error should keep its own key: %s", buf.String())
+	}
+}
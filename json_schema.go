@@ -0,0 +1,361 @@
+package slog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LevelStyle 决定 JSON 输出中级别字段的表示方式。
+type LevelStyle int
+
+const (
+	// LevelStyleName 使用注册的级别名称，如 "Info"（默认）。
+	LevelStyleName LevelStyle = iota
+	// LevelStyleUpper 使用大写名称，如 "INFO"。
+	LevelStyleUpper
+	// LevelStyleLower 使用小写名称，如 "info"。
+	LevelStyleLower
+	// LevelStyleNumeric 使用级别数值，如 Info 为 0、Error 为 8。
+	LevelStyleNumeric
+)
+
+// TimeEncoding 决定 JSON 输出中时间字段的编码方式。
+type TimeEncoding int
+
+const (
+	// TimeEncodingLayout 按 JSONSchema.TimeLayout 格式化为字符串（默认）。
+	TimeEncodingLayout TimeEncoding = iota
+	// TimeEncodingEpochSeconds 输出 Unix 秒。
+	TimeEncodingEpochSeconds
+	// TimeEncodingEpochMillis 输出 Unix 毫秒。
+	TimeEncodingEpochMillis
+	// TimeEncodingEpochMicros 输出 Unix 微秒。
+	TimeEncodingEpochMicros
+	// TimeEncodingEpochNanos 输出 Unix 纳秒。
+	TimeEncodingEpochNanos
+)
+
+// SourceStyle 决定 JSON 输出中源码位置字段的表示方式。
+type SourceStyle int
+
+const (
+	// SourceStyleObject 输出 {"function","file","line"} 对象（默认）。
+	SourceStyleObject SourceStyle = iota
+	// SourceStyleString 输出 "file.go:42" 字符串。
+	SourceStyleString
+)
+
+// JSONSchema 定制 JSON 输出中内置字段的键名与编码，同时作用于订阅方的 JSON 渲染。
+// 只影响顶层内置字段，用户属性保持原样。零值等价于默认输出。
+type JSONSchema struct {
+	TimeKey    string // 时间字段键名，为空时使用 "time"
+	LevelKey   string // 级别字段键名，为空时使用 "level"
+	MessageKey string // 消息字段键名，为空时使用 "msg"
+	SourceKey  string // 源码位置字段键名，为空时使用 "source"
+
+	LevelStyle   LevelStyle
+	TimeEncoding TimeEncoding
+	TimeLayout   string // TimeEncodingLayout 使用的格式，为空时使用全局 TimeFormat
+	UTC          bool   // 格式化前将时间转换为 UTC
+	SourceStyle  SourceStyle
+
+	// Profile 选择内置字段映射配置（JSONProfileECS / JSONProfileOTel），上面的非零字段覆盖配置默认值。
+	Profile string
+}
+
+func (s *JSONSchema) isZero() bool {
+	return s == nil || *s == JSONSchema{}
+}
+
+// resolve 合并 Profile 默认值与显式字段，返回生效的 schema 与映射配置。
+func (s *JSONSchema) resolve() (JSONSchema, *jsonProfile, error) {
+	if s == nil {
+		return JSONSchema{}, nil, nil
+	}
+	if s.Profile == "" {
+		return *s, nil, nil
+	}
+	profile, err := lookupJSONProfile(s.Profile)
+	if err != nil {
+		schema := *s
+		schema.Profile = ""
+		return schema, nil, err
+	}
+	merged := profile.schema
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&merged.TimeKey, s.TimeKey}, {&merged.LevelKey, s.LevelKey},
+		{&merged.MessageKey, s.MessageKey}, {&merged.SourceKey, s.SourceKey},
+		{&merged.TimeLayout, s.TimeLayout},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	if s.LevelStyle != LevelStyleName {
+		merged.LevelStyle = s.LevelStyle
+	}
+	if s.TimeEncoding != TimeEncodingLayout {
+		merged.TimeEncoding = s.TimeEncoding
+	}
+	if s.SourceStyle != SourceStyleObject {
+		merged.SourceStyle = s.SourceStyle
+	}
+	merged.UTC = merged.UTC || s.UTC
+	merged.Profile = s.Profile
+	return merged, profile, nil
+}
+
+// Validate 检查 Profile 是否为已知的映射配置。
+func (s *JSONSchema) Validate() error {
+	_, _, err := s.resolve()
+	return err
+}
+
+// replaceAttr 返回应用该 schema 的 ReplaceAttr，layout 为 TimeLayout 为空时的回退格式。
+// 需位于通用格式化器之前执行，以便拿到原始的 time.Time / Level / *Source 值；
+// 生成的 handler 需经 builtinKeyGuard 包装，同名用户属性才不会被改写。
+// profile 非 nil 且未显式指定级别 / 源码位置的键名与样式时，由 profile 决定其输出。
+func (s *JSONSchema) replaceAttr(layout string, loc *time.Location, profile *jsonProfile) func([]string, slog.Attr) slog.Attr {
+	if s.isZero() && profile == nil {
+		return nil
+	}
+	schema := *s
+	if schema.TimeLayout != "" {
+		layout = schema.TimeLayout
+	}
+	var encodeLevel func(Level) slog.Attr
+	var encodeSource func(*slog.Source) slog.Attr
+	if profile != nil {
+		if profile.level != nil && schema.LevelKey == "" && schema.LevelStyle == LevelStyleName {
+			encodeLevel = profile.level
+		}
+		if schema.SourceKey == "" && schema.SourceStyle == SourceStyleObject {
+			encodeSource = profile.profileSource
+		}
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) != 0 {
+			return a
+		}
+		// 与内置字段同名的用户属性已由 builtinKeyGuard 加上标记，恢复原键后原样返回；
+		// 其余顶层的 time / level / msg / source 只可能来自内置字段。
+		if key, ok := strings.CutPrefix(a.Key, builtinKeyMarker); ok {
+			a.Key = key
+			return a
+		}
+		switch a.Key {
+		case slog.TimeKey:
+			if t, ok := timeFromValue(a.Value); ok {
+				if loc != nil {
+					t = t.In(loc)
+				}
+				a.Key, a.Value = schemaKey(schema.TimeKey, a.Key), schema.encodeTime(t, layout)
+			}
+		case slog.LevelKey:
+			if level, ok := levelFromValue(a.Value); ok {
+				if encodeLevel != nil {
+					return encodeLevel(level)
+				}
+				a.Key, a.Value = schemaKey(schema.LevelKey, a.Key), schema.encodeLevel(level)
+			}
+		case slog.MessageKey:
+			a.Key = schemaKey(schema.MessageKey, a.Key)
+		case slog.SourceKey:
+			if src := sourceFromValue(a.Value); src != nil {
+				if encodeSource != nil {
+					return encodeSource(src)
+				}
+				a.Key, a.Value = schemaKey(schema.SourceKey, a.Key), schema.encodeSource(src)
+			}
+		}
+		return a
+	}
+}
+
+// builtinKeyMarker 是 builtinKeyGuard 给同名用户属性加的键前缀，由 schema 的 ReplaceAttr 去掉。
+const builtinKeyMarker = "\x00slog-user:"
+
+// builtinKeyGuard 给与内置字段同名的顶层用户属性加上标记，使 schema 的 ReplaceAttr
+// 只改写内置字段，不依赖值的类型区分。
+type builtinKeyGuard struct {
+	next    slog.Handler
+	grouped bool // 已打开分组，之后的属性不在顶层
+}
+
+func (h *builtinKeyGuard) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *builtinKeyGuard) Handle(ctx context.Context, r slog.Record) error {
+	if h.grouped || !recordHasBuiltinKey(r) {
+		return h.next.Handle(ctx, r)
+	}
+	marked := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		marked.AddAttrs(markBuiltinKey(a))
+		return true
+	})
+	return h.next.Handle(ctx, marked)
+}
+
+func (h *builtinKeyGuard) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if !h.grouped {
+		marked := make([]slog.Attr, len(attrs))
+		for i, a := range attrs {
+			marked[i] = markBuiltinKey(a)
+		}
+		attrs = marked
+	}
+	return &builtinKeyGuard{next: h.next.WithAttrs(attrs), grouped: h.grouped}
+}
+
+func (h *builtinKeyGuard) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &builtinKeyGuard{next: h.next.WithGroup(name), grouped: true}
+}
+
+func isBuiltinKey(key string) bool {
+	switch key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+		return true
+	}
+	return false
+}
+
+func recordHasBuiltinKey(r slog.Record) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = attrHasBuiltinKey(a)
+		return !found
+	})
+	return found
+}
+
+// attrHasBuiltinKey 判断属性（含内联的空键分组）是否在顶层产生内置字段名。
+func attrHasBuiltinKey(a slog.Attr) bool {
+	if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+		return slices.ContainsFunc(a.Value.Group(), attrHasBuiltinKey)
+	}
+	return isBuiltinKey(a.Key) && a.Value.Resolve().Kind() != slog.KindGroup
+}
+
+func markBuiltinKey(a slog.Attr) slog.Attr {
+	if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		marked := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			marked[i] = markBuiltinKey(ga)
+		}
+		a.Value = slog.GroupValue(marked...)
+		return a
+	}
+	// log/slog 不会对分组调用 ReplaceAttr，同名分组本就不会被改写，加标记反而无法去掉
+	if isBuiltinKey(a.Key) && a.Value.Resolve().Kind() != slog.KindGroup {
+		a.Key = builtinKeyMarker + a.Key
+	}
+	return a
+}
+
+func schemaKey(custom, def string) string {
+	if custom != "" {
+		return custom
+	}
+	return def
+}
+
+func (s JSONSchema) encodeTime(t time.Time, layout string) slog.Value {
+	if s.UTC {
+		t = t.UTC()
+	}
+	switch s.TimeEncoding {
+	case TimeEncodingEpochSeconds:
+		return slog.Int64Value(t.Unix())
+	case TimeEncodingEpochMillis:
+		return slog.Int64Value(t.UnixMilli())
+	case TimeEncodingEpochMicros:
+		return slog.Int64Value(t.UnixMicro())
+	case TimeEncodingEpochNanos:
+		return slog.Int64Value(t.UnixNano())
+	default:
+		return slog.StringValue(t.Format(layout))
+	}
+}
+
+func (s JSONSchema) encodeLevel(level Level) slog.Value {
+	if s.LevelStyle == LevelStyleNumeric {
+		return slog.IntValue(int(level))
+	}
+	name, ok := levelJSONName(level)
+	if !ok {
+		name = level.String()
+	}
+	switch s.LevelStyle {
+	case LevelStyleUpper:
+		name = strings.ToUpper(name)
+	case LevelStyleLower:
+		name = strings.ToLower(name)
+	}
+	return slog.StringValue(name)
+}
+
+func (s JSONSchema) encodeSource(src *slog.Source) slog.Value {
+	file := filepath.Base(src.File)
+	if s.SourceStyle == SourceStyleString {
+		return slog.StringValue(file + ":" + strconv.Itoa(src.Line))
+	}
+	copy := *src
+	copy.File = file
+	return slog.AnyValue(&copy)
+}
+
+func timeFromValue(val slog.Value) (time.Time, bool) {
+	switch val.Kind() {
+	case slog.KindTime:
+		return val.Time(), true
+	case slog.KindAny:
+		t, ok := val.Any().(time.Time)
+		return t, ok
+	}
+	return time.Time{}, false
+}
+
+// newJSONHandler 在 base 选项之上叠加 schema 创建 JSON handler；选择了 Profile 时外层包裹属性映射。
+// layout 与 loc 为实例的时间设置，layout 为空时使用全局 TimeFormat。
+// 未知的 Profile 被忽略，调用方可先用 Validate 检查，Logger 通过 ConfigError 暴露该错误。
+func (s *JSONSchema) newJSONHandler(w io.Writer, base *slog.HandlerOptions, layout string, loc *time.Location) slog.Handler {
+	schema, profile, _ := s.resolve()
+	if layout == "" {
+		layout = currentTimeFormat()
+	}
+	replace := schema.replaceAttr(layout, loc, profile)
+	if replace == nil {
+		return NewJSONHandler(w, base)
+	}
+	opts := *base
+	opts.ReplaceAttr = chainReplaceAttr(replace, base.ReplaceAttr)
+	if profile == nil {
+		return &builtinKeyGuard{next: NewJSONHandler(w, &opts)}
+	}
+	addSource := opts.AddSource
+	if profile.attrsKey != "" {
+		// 源码位置作为属性写入 attrsKey 分组，由 profileHandler 负责。
+		opts.AddSource = false
+	}
+	return &profileHandler{
+		next:      &builtinKeyGuard{next: NewJSONHandler(w, &opts)},
+		profile:   profile,
+		addSource: addSource,
+		attrs:     slices.Clip(profile.static),
+	}
+}
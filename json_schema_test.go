@@ -0,0 +1,148 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONSchemaRenamesAndEncodes(t *testing.T) {
+	records, cancel := Subscribe(1)
+	defer cancel()
+
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.AddSource = true
+		c.JSONSchema = &JSONSchema{
+			TimeKey:      "@timestamp",
+			LevelKey:     "severity",
+			MessageKey:   "message",
+			SourceKey:    "caller",
+			LevelStyle:   LevelStyleNumeric,
+			TimeEncoding: TimeEncodingEpochMillis,
+			SourceStyle:  SourceStyleString,
+		}
+	})
+	before := time.Now().UnixMilli()
+	logger.Warn("disk low", "time", "user-attr")
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if ts, _ := payload["@timestamp"].(float64); int64(ts) < before {
+		t.Fatalf("expected epoch millis timestamp: %s", buf.String())
+	}
+	if payload["time"] != "user-attr" {
+		t.Fatalf("user attr named time must be untouched: %s", buf.String())
+	}
+	if payload["severity"] != float64(LevelWarn) || payload["message"] != "disk low" {
+		t.Fatalf("unexpected level/message: %s", buf.String())
+	}
+	if caller, _ := payload["caller"].(string); !strings.HasPrefix(caller, "json_schema_test.go:") {
+		t.Fatalf("expected string source, got %v", payload["caller"])
+	}
+	for _, key := range []string{"level", "msg", "source"} {
+		if _, ok := payload[key]; ok {
+			t.Fatalf("default key %q must be renamed: %s", key, buf.String())
+		}
+	}
+
+	select {
+	case event := <-records:
+		if event.Format != "json" || !strings.Contains(event.Rendered, `"severity":4`) ||
+			!strings.Contains(event.Rendered, `"message":"disk low"`) {
+			t.Fatalf("subscription rendering must follow schema: %q", event.Rendered)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected subscription event")
+	}
+}
+
+func TestJSONSchemaLevelCasingAndUTC(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.JSONSchema = &JSONSchema{LevelStyle: LevelStyleUpper, TimeLayout: time.RFC3339, UTC: true}
+	})
+	logger.Info("hello")
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if payload["level"] != "INFO" {
+		t.Fatalf("expected upper-case level: %s", buf.String())
+	}
+	if ts, _ := payload["time"].(string); !strings.HasSuffix(ts, "Z") {
+		t.Fatalf("expected UTC RFC3339 time, got %q", ts)
+	}
+}
+
+func TestJSONSchemaKeepsSameTypedUserAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.JSONSchema = &JSONSchema{TimeKey: "ts", LevelKey: "severity", MessageKey: "message"}
+	})
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger.With("msg", "bound").Info("real", "time", at, "level", LevelWarn, slog.Group("", slog.String("msg", "inline")))
+
+	// 同名用户属性不得被改写成 schema 键，否则会与内置字段重复。
+	for _, key := range []string{`"ts":`, `"severity":`, `"message":`} {
+		if n := strings.Count(buf.String(), key); n != 1 {
+			t.Fatalf("expected one %s, got %d: %s", key, n, buf.String())
+		}
+	}
+	for _, want := range []string{`"message":"real"`, `"msg":"bound"`, `"msg":"inline"`, `"time":"2024`, `"level":"Warn"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expected %s in %s", want, buf.String())
+		}
+	}
+}
+
+func TestLoggerRecordsUnknownJSONProfile(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LogInternalErrors = false
+	cfg.JSONSchema = &JSONSchema{Profile: "unknown"}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected Config.Validate error")
+	}
+	if err := NewLoggerWithConfig(&bytes.Buffer{}, cfg).With("k", "v").ConfigError(); err == nil {
+		t.Fatal("expected ConfigError to report unknown profile")
+	}
+	if err := NewLoggerWithConfig(&bytes.Buffer{}, DefaultConfig()).ConfigError(); err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+}
+
+func TestJSONSchemaUserGroupsWithBuiltinKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.JSONSchema = &JSONSchema{TimeKey: "ts", LevelKey: "severity", MessageKey: "message", SourceKey: "caller"}
+	})
+	logger.With(slog.Group("source", slog.String("repo", "x"))).Info("real",
+		slog.Group("time", slog.Int("zone", 8)),
+		slog.Group("level", slog.String("name", "custom")),
+		slog.Group("msg", slog.String("text", "inline")),
+	)
+
+	out := buf.String()
+	if strings.Contains(out, "slog-user") {
+		t.Fatalf("internal marker leaked: %s", out)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	for key, field := range map[string]string{"source": "repo", "time": "zone", "level": "name", "msg": "text"} {
+		group, ok := payload[key].(map[string]any)
+		if !ok || group[field] == nil {
+			t.Fatalf("expected user group %q, got %s", key, out)
+		}
+	}
+	if payload["message"] != "real" {
+		t.Fatalf("unexpected message: %s", out)
+	}
+}
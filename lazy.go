@@ -0,0 +1,114 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// lazyValue 是 Lazy / LazyGroup 创建的延迟值，记录分发前会被替换为单条记录内共享的 lazyOnce。
+type lazyValue struct {
+	fn    func() any
+	group func() []Attr
+}
+
+// LogValue 直接求值，供未经 Logger 分发（如直接交给标准库 Handler）的场景使用。
+func (v lazyValue) LogValue() Value {
+	return v.resolve()
+}
+
+func (v lazyValue) resolve() Value {
+	if v.group != nil {
+		return slog.GroupValue(v.group()...)
+	}
+	return slog.AnyValue(v.fn())
+}
+
+// lazyOnce 在一条记录内只求值一次，text / json / 订阅等多路输出共享结果。
+type lazyOnce struct {
+	once  sync.Once
+	lazy  lazyValue
+	value Value
+}
+
+func (v *lazyOnce) LogValue() Value {
+	v.once.Do(func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				v.value = slog.StringValue(fmt.Sprintf("!PANIC: %v", rec))
+			}
+		}()
+		v.value = v.lazy.resolve()
+	})
+	return v.value
+}
+
+// Lazy 创建延迟求值属性：fn 仅在记录通过级别判断、被某个输出处理时调用，
+// 且同一条记录分发到多个输出时只调用一次。formatter 与 DLP 看到的是求值结果。
+//
+//	logger.Debug("request", slog.Lazy("body", func() any { return dump(req) }))
+func Lazy(key string, fn func() any) Attr {
+	if fn == nil {
+		return slog.Any(key, nil)
+	}
+	return slog.Any(key, lazyValue{fn: fn})
+}
+
+// LazyGroup 创建延迟求值的属性组，语义同 Lazy。
+func LazyGroup(key string, fn func() []Attr) Attr {
+	if fn == nil {
+		return slog.Group(key)
+	}
+	return slog.Any(key, lazyValue{group: fn})
+}
+
+// bindLazyAttrs 为记录中的延迟属性（含分组内）绑定单条记录级的求值缓存。
+func bindLazyAttrs(r *slog.Record) {
+	if r.NumAttrs() == 0 {
+		return
+	}
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = hasLazyAttr(a)
+		return !found
+	})
+	if !found {
+		return
+	}
+	attrs := RecordAttrs(*r)
+	for i := range attrs {
+		attrs[i] = bindLazyAttr(attrs[i])
+	}
+	SetRecordAttrs(r, attrs...)
+}
+
+func hasLazyAttr(a slog.Attr) bool {
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		_, ok := a.Value.Any().(lazyValue)
+		return ok
+	case slog.KindGroup:
+		return slices.ContainsFunc(a.Value.Group(), hasLazyAttr)
+	}
+	return false
+}
+
+func bindLazyAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		if lv, ok := a.Value.Any().(lazyValue); ok {
+			a.Value = slog.AnyValue(&lazyOnce{lazy: lv})
+		}
+	case slog.KindGroup:
+		group := a.Value.Group()
+		if slices.ContainsFunc(group, hasLazyAttr) {
+			bound := make([]slog.Attr, len(group))
+			for i, child := range group {
+				bound[i] = bindLazyAttr(child)
+			}
+			a.Value = slog.GroupValue(bound...)
+		}
+	}
+	return a
+}
@@ -0,0 +1,74 @@
+package slog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLazySkippedWhenDisabled(t *testing.T) {
+	resetForTest()
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.SetEnableText(true)
+	cfg.SetEnableJSON(true)
+	logger := NewLoggerWithConfig(&buf, cfg)
+	logger.SetLevel(LevelInfo)
+
+	var calls atomic.Int32
+	logger.Debug("hidden", Lazy("body", func() any { calls.Add(1); return "x" }))
+	if calls.Load() != 0 || buf.Len() != 0 {
+		t.Fatalf("lazy value must not resolve for disabled records, calls=%d out=%q", calls.Load(), buf.String())
+	}
+}
+
+func TestLazyResolvedOncePerRecord(t *testing.T) {
+	resetForTest()
+	formatterID := RegisterFormatter("upper-body", func(_ []string, attr slog.Attr) (slog.Value, bool) {
+		if attr.Key == "body" {
+			return slog.StringValue(strings.ToUpper(attr.Value.String())), true
+		}
+		return attr.Value, false
+	})
+	defer RemoveFormatter(formatterID)
+
+	records, cancel := Subscribe(4)
+	defer cancel()
+
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.NoColor = true
+	cfg.SetEnableText(true)
+	cfg.SetEnableJSON(true)
+	var calls atomic.Int32
+	logger := NewLoggerWithConfig(&buf, cfg).With(Lazy("body", func() any { calls.Add(1); return "payload" }))
+
+	logger.WithGroup("req").Info("first", LazyGroup("meta", func() []Attr {
+		calls.Add(1)
+		return []Attr{slog.Int("size", 7)}
+	}))
+	select {
+	case event := <-records:
+		attrs := flattenRecordAttrs(event.Record)
+		if attrs["req.body"] != "PAYLOAD" || attrs["req.meta.size"] != "7" {
+			t.Fatalf("unexpected publish view attrs: %v", attrs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected subscription event")
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected each lazy value to resolve once across fanout, got %d", calls.Load())
+	}
+	out := buf.String()
+	if !strings.Contains(out, "req.body=PAYLOAD") || !strings.Contains(out, `"body":"PAYLOAD"`) || !strings.Contains(out, `"size":7`) {
+		t.Fatalf("formatter must see resolved value in text and json:\n%s", out)
+	}
+
+	logger.Info("second")
+	if calls.Load() != 3 {
+		t.Fatalf("bound lazy attr must resolve again for a new record, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,86 @@
+package slog
+
+import (
+	"github.com/darkit/slog/modules"
+)
+
+// RegisterLevel 注册自定义级别（如 NOTICE / AUDIT / SECURITY）。
+//   - name: JSON / logfmt / GELF 等结构化输出使用的名称，同时用于 SetLevel 与 ApplyRuntimeOption("level") 解析
+//   - shortName: 控制台短名，为空时取 name 首字母
+//   - color: 控制台 ANSI 颜色序列，为空时沿用不高于该数值的最近内置级别颜色
+//   - syslogSeverity: GELF / syslog 使用的严重度 0-7
+//
+// 内置的六个级别不可重新定义。
+func RegisterLevel(value Level, name, shortName, color string, syslogSeverity int) error {
+	err := modules.RegisterLevel(modules.LevelDescriptor{
+		Level:    value,
+		Name:     name,
+		Short:    shortName,
+		Color:    color,
+		Severity: syslogSeverity,
+	})
+	if err != nil {
+		return NewInvalidInputError("level", "valid custom level definition", err.Error())
+	}
+	return nil
+}
+
+// UnregisterLevel 移除自定义级别。
+func UnregisterLevel(value Level) bool {
+	return modules.UnregisterLevel(value)
+}
+
+// ParseLevel 按名称（不区分大小写）解析内置或已注册的级别。
+func ParseLevel(name string) (Level, error) {
+	if level, ok := modules.ParseLevel(name); ok {
+		return level, nil
+	}
+	return 0, NewInvalidInputError("level", "registered level name", name)
+}
+
+// LevelName 返回级别在结构化输出中的名称，未注册时为 slog 默认格式（如 "INFO+2"）。
+func LevelName(level Level) string {
+	return modules.LevelName(level)
+}
+
+func levelTextName(level Level) string {
+	if d, ok := modules.LookupLevel(level); ok {
+		return d.Short
+	}
+	return level.String()
+}
+
+func levelJSONName(level Level) (string, bool) {
+	if d, ok := modules.LookupLevel(level); ok {
+		return d.Name, true
+	}
+	return "", false
+}
+
+// levelColor 返回控制台颜色：内置级别取固定颜色，自定义级别优先使用注册颜色，
+// 否则取不高于 level 的最近已着色级别；未注册的数值级别与原先一致，显示为红色。
+func levelColor(level Level) string {
+	d, ok := modules.LookupLevel(level)
+	if !ok {
+		return ansiBrightRed
+	}
+	if d.Color != "" {
+		return d.Color
+	}
+	color := ansiBrightRed
+	for _, d := range modules.Levels() {
+		if d.Level > level {
+			break
+		}
+		if d.Color != "" {
+			color = d.Color
+		}
+	}
+	return color
+}
+
+// isValidLevel 检查日志级别是否为内置或已注册级别
+func isValidLevel(level Level) bool {
+	_, ok := modules.LookupLevel(level)
+	return ok
+}
@@ -0,0 +1,112 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelOverrideRule 按上下文字段（WithValue 设置）匹配，为命中的上下文放开到 Level。
+// 例如 {Key: "user_id", Values: []any{"u-1", "u-2"}, Level: LevelDebug}。
+type LevelOverrideRule struct {
+	Key    string
+	Values []any
+	Level  Level
+}
+
+type levelOverrideEntry struct {
+	id     string
+	key    string
+	values map[string]struct{}
+	level  Level
+}
+
+var (
+	levelOverrideMu     sync.Mutex
+	levelOverrideRules  atomic.Pointer[[]levelOverrideEntry]
+	levelOverrideNextID atomic.Int64
+)
+
+// AddLevelOverrideRule 注册基于上下文字段的级别覆盖规则，返回可用于移除的 ID。
+func AddLevelOverrideRule(rule LevelOverrideRule) (string, error) {
+	if rule.Key == "" {
+		return "", NewInvalidInputError("rule.Key", "non-empty field key", "empty")
+	}
+	if len(rule.Values) == 0 {
+		return "", NewInvalidInputError("rule.Values", "at least one value", "empty")
+	}
+	entry := levelOverrideEntry{
+		id:     fmt.Sprintf("%s-%d", rule.Key, levelOverrideNextID.Add(1)),
+		key:    rule.Key,
+		values: make(map[string]struct{}, len(rule.Values)),
+		level:  rule.Level,
+	}
+	for _, v := range rule.Values {
+		entry.values[fmt.Sprint(v)] = struct{}{}
+	}
+
+	levelOverrideMu.Lock()
+	defer levelOverrideMu.Unlock()
+	rules := append(currentLevelOverrideRules(), entry)
+	levelOverrideRules.Store(&rules)
+	return entry.id, nil
+}
+
+// RemoveLevelOverrideRule 根据 ID 移除规则。
+func RemoveLevelOverrideRule(id string) bool {
+	levelOverrideMu.Lock()
+	defer levelOverrideMu.Unlock()
+	current := currentLevelOverrideRules()
+	for i, entry := range current {
+		if entry.id == id {
+			rules := append(current[:i:i], current[i+1:]...)
+			levelOverrideRules.Store(&rules)
+			return true
+		}
+	}
+	return false
+}
+
+// ClearLevelOverrideRules 清空所有基于字段的级别覆盖规则。
+func ClearLevelOverrideRules() {
+	levelOverrideMu.Lock()
+	defer levelOverrideMu.Unlock()
+	levelOverrideRules.Store(nil)
+}
+
+func currentLevelOverrideRules() []levelOverrideEntry {
+	if rules := levelOverrideRules.Load(); rules != nil {
+		return *rules
+	}
+	return nil
+}
+
+// contextLevelOverride 返回 ctx 上生效的最低覆盖级别：显式覆盖与命中规则中取较低者。
+func contextLevelOverride(ctx context.Context) (Level, bool) {
+	fields := getFields(ctx)
+	if fields == nil {
+		return 0, false
+	}
+	rules := currentLevelOverrideRules()
+
+	fields.mu.RLock()
+	defer fields.mu.RUnlock()
+	level, ok := fields.levelOverride, fields.hasLevel
+	for _, rule := range rules {
+		v, exists := fields.values[rule.key]
+		if !exists {
+			continue
+		}
+		if _, hit := rule.values[fmt.Sprint(v)]; hit && (!ok || rule.level < level) {
+			level, ok = rule.level, true
+		}
+	}
+	return level, ok
+}
+
+// levelOverrideEnables 判断上下文覆盖是否放开了 level。
+func levelOverrideEnables(ctx context.Context, level Level) bool {
+	override, ok := contextLevelOverride(ctx)
+	return ok && level >= override
+}
@@ -0,0 +1,74 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWithLevelOverrideEnablesDebugForContextOnly(t *testing.T) {
+	resetForTest()
+	SetLevelInfo()
+	defer resetForTest()
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false)
+	debugging := logger.WithValue("request_id", "r-1").WithLevelOverride(LevelDebug)
+
+	logger.Debug("hidden")
+	debugging.Debug("visible")
+	debugging.Trace("still hidden")
+	debugging.WithValue("step", 2).Debug("inherited")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Fatalf("records outside the override must stay filtered:\n%s", out)
+	}
+	if !strings.Contains(out, "visible") || !strings.Contains(out, "inherited") {
+		t.Fatalf("expected override to emit debug records:\n%s", out)
+	}
+	if !debugging.Enabled(debugging.ctx, LevelDebug) || logger.Enabled(context.Background(), LevelDebug) {
+		t.Fatal("Logger.Enabled must honor the context override")
+	}
+
+	ctx := WithLevelOverride(context.Background(), LevelTrace)
+	if !logger.Enabled(ctx, LevelTrace) || logger.Enabled(WithoutLevelOverride(ctx), LevelTrace) {
+		t.Fatal("unexpected Enabled result for context override")
+	}
+}
+
+func TestLevelOverrideRuleMatchesContextFields(t *testing.T) {
+	resetForTest()
+	SetLevelInfo()
+	defer resetForTest()
+	defer ClearLevelOverrideRules()
+
+	if _, err := AddLevelOverrideRule(LevelOverrideRule{Key: "user_id"}); err == nil {
+		t.Fatal("expected error for rule without values")
+	}
+	id, err := AddLevelOverrideRule(LevelOverrideRule{Key: "user_id", Values: []any{"alice", 42}, Level: LevelDebug})
+	if err != nil {
+		t.Fatalf("add rule: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false)
+	logger.WithValue("user_id", "alice").Debug("alice debug")
+	logger.WithValue("user_id", 42).Debug("numeric debug")
+	logger.WithValue("user_id", "bob").Debug("bob debug")
+
+	out := buf.String()
+	if !strings.Contains(out, "alice debug") || !strings.Contains(out, "numeric debug") || strings.Contains(out, "bob debug") {
+		t.Fatalf("unexpected rule matching output:\n%s", out)
+	}
+
+	if !RemoveLevelOverrideRule(id) {
+		t.Fatal("expected rule to be removed")
+	}
+	buf.Reset()
+	logger.WithValue("user_id", "alice").Debug("after removal")
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output after rule removal, got:\n%s", buf.String())
+	}
+}
@@ -2,6 +2,7 @@ package slog
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 )
@@ -131,3 +132,67 @@ func TestGlobalVsInstanceLevel(t *testing.T) {
 		t.Logf("全局Debug消息被正确过滤")
 	}
 }
+
+func TestRegisterLevelAcrossOutputs(t *testing.T) {
+	resetForTest()
+	defer resetForTest()
+
+	const levelNotice Level = 2
+	if err := RegisterLevel(levelNotice, "Notice", "N", ansiCyan, 5); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	defer UnregisterLevel(levelNotice)
+	if err := RegisterLevel(LevelWarn, "Warning", "W", "", 4); err == nil {
+		t.Fatal("expected builtin levels to be protected")
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false)
+	logger.Log(context.Background(), levelNotice, "audit trail")
+	if out := buf.String(); !strings.Contains(out, "[N]") || !strings.Contains(out, "audit trail") {
+		t.Fatalf("expected console short name, got %q", out)
+	}
+
+	buf.Reset()
+	setGlobalTextEnabled(false)
+	setGlobalJSONEnabled(true)
+	logger.Log(context.Background(), levelNotice, "audit json")
+	if out := buf.String(); !strings.Contains(out, `"level":"Notice"`) {
+		t.Fatalf("expected JSON level name, got %q", out)
+	}
+
+	if _, err := ApplyRuntimeOption("level", "notice"); err != nil {
+		t.Fatalf("ApplyRuntimeOption: %v", err)
+	}
+	if GetLevel() != levelNotice {
+		t.Fatalf("expected level %v, got %v", levelNotice, GetLevel())
+	}
+	if level, err := ParseLevel("Notice"); err != nil || level != levelNotice {
+		t.Fatalf("ParseLevel = %v, %v", level, err)
+	}
+	if LevelName(levelNotice) != "Notice" || levelColor(levelNotice) != ansiCyan {
+		t.Fatal("unexpected level rendering lookups")
+	}
+}
+
+func TestUnregisteredLevelsKeepBaselineRendering(t *testing.T) {
+	const levelAudit Level = 6
+	if err := RegisterLevel(levelAudit, "Audit", "A", "", 4); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	// 未设置颜色的自定义级别沿用最近内置级别颜色
+	if got := levelColor(levelAudit); got != ansiBrightYellow {
+		t.Fatalf("custom level color = %q, want warn color", got)
+	}
+	UnregisterLevel(levelAudit)
+
+	// 未注册的数值级别与引入级别注册表之前一致：红色、slog 默认名称
+	if got := levelColor(levelAudit); got != ansiBrightRed {
+		t.Fatalf("unregistered level color = %q, want red", got)
+	}
+	var buf bytes.Buffer
+	New(NewConsoleHandler(&buf, false, &HandlerOptions{Level: LevelTrace}, WithColorMode(ColorAlways))).Log(context.Background(), levelAudit, "x")
+	if !strings.Contains(buf.String(), ansiBrightRed+"[WARN+2]") {
+		t.Fatalf("unexpected console rendering: %q", buf.String())
+	}
+}
@@ -0,0 +1,297 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// 截断相关的默认值与属性名。
+const (
+	DefaultTruncationMarker = "...[truncated]"
+	// AttrsDroppedKey 记录因数量或总大小限制被丢弃的属性个数。
+	AttrsDroppedKey = "attrs_dropped"
+	// maxDepthPlaceholder 替换超出 MaxGroupDepth 的分组。
+	maxDepthPlaceholder = "[max depth]"
+)
+
+// TruncationStats 是尺寸限制触发次数统计。
+type TruncationStats struct {
+	Values  uint64 `json:"values"`  // 被截断的字符串值
+	Attrs   uint64 `json:"attrs"`   // 被丢弃的属性
+	Groups  uint64 `json:"groups"`  // 因嵌套过深被替换的分组
+	Records uint64 `json:"records"` // 超出 MaxRecordBytes 的记录
+}
+
+var truncationCounters struct {
+	values, attrs, groups, records atomic.Uint64
+}
+
+func getTruncationStats() TruncationStats {
+	return TruncationStats{
+		Values:  truncationCounters.values.Load(),
+		Attrs:   truncationCounters.attrs.Load(),
+		Groups:  truncationCounters.groups.Load(),
+		Records: truncationCounters.records.Load(),
+	}
+}
+
+func resetTruncationStats() {
+	truncationCounters.values.Store(0)
+	truncationCounters.attrs.Store(0)
+	truncationCounters.groups.Store(0)
+	truncationCounters.records.Store(0)
+}
+
+func (c *Config) hasSizeLimits() bool {
+	return c != nil && (c.MaxValueBytes > 0 || c.MaxAttrs > 0 || c.MaxGroupDepth > 0 || c.MaxRecordBytes > 0)
+}
+
+func (c *Config) truncationMarker() string {
+	if c.TruncationMarker != "" {
+		return c.TruncationMarker
+	}
+	return DefaultTruncationMarker
+}
+
+// recordLimiter 对单条记录应用 Config 中的尺寸限制，触发次数先在本地累计，处理完成后再计入统计。
+type recordLimiter struct {
+	cfg     *Config
+	marker  string
+	budget  int // 剩余可保留的属性数，MaxAttrs 为 0 时为 -1
+	dropped int
+	stats   TruncationStats
+}
+
+// applySizeLimits 在分发前约束记录：截断超长值、限制属性数与分组深度，最后按 MaxRecordBytes 收缩。
+// 仅对至少有一路输出接收的记录调用。LogValuer（含 Lazy）在此先求值，限制作用于求值结果，
+// Lazy 的结果随记录共享，各输出不会再次求值。调用栈属性（Stacktrace 值）不参与限制，
+// attrs_dropped 计入 MaxAttrs 与 MaxRecordBytes。
+// MaxValueBytes 同时作用于上下文字段，返回的 ctx 携带截断后的字段副本。
+func (l *Logger) applySizeLimits(ctx context.Context, r *slog.Record) context.Context {
+	cfg := l.config
+	if !cfg.hasSizeLimits() {
+		return ctx
+	}
+	lim := &recordLimiter{cfg: cfg, marker: cfg.truncationMarker(), budget: -1}
+	if cfg.MaxAttrs > 0 {
+		lim.budget = cfg.MaxAttrs
+	}
+
+	source := RecordAttrs(*r)
+	attrs := lim.walk(source, 0)
+	if lim.dropped > 0 && cfg.MaxAttrs > 0 {
+		// 为 attrs_dropped 预留一个名额，保证输出的属性数不超过 MaxAttrs
+		lim.reset(cfg.MaxAttrs - 1)
+		attrs = lim.walk(source, 0)
+	}
+	msg := r.Message
+	if cfg.MaxRecordBytes > 0 {
+		attrs, msg = lim.shrink(msg, attrs)
+	}
+	if lim.dropped > 0 {
+		lim.stats.Attrs += uint64(lim.dropped)
+		attrs = append(attrs, slog.Int(AttrsDroppedKey, lim.dropped))
+	}
+	ctx = lim.boundContextFields(ctx)
+
+	nr := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	nr.AddAttrs(attrs...)
+	*r = nr
+
+	truncationCounters.values.Add(lim.stats.Values)
+	truncationCounters.attrs.Add(lim.stats.Attrs)
+	truncationCounters.groups.Add(lim.stats.Groups)
+	truncationCounters.records.Add(lim.stats.Records)
+	return ctx
+}
+
+// reset 以新的属性名额重新开始统计。
+func (lim *recordLimiter) reset(budget int) {
+	lim.budget, lim.dropped, lim.stats = budget, 0, TruncationStats{}
+}
+
+func (lim *recordLimiter) walk(attrs []slog.Attr, depth int) []slog.Attr {
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if isStacktraceAttr(a) {
+			out = append(out, a)
+			continue
+		}
+		if lim.budget == 0 {
+			lim.dropped++
+			continue
+		}
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			if lim.cfg.MaxGroupDepth > 0 && depth >= lim.cfg.MaxGroupDepth {
+				lim.stats.Groups++
+				a.Value = slog.StringValue(maxDepthPlaceholder)
+			} else {
+				children := lim.walk(a.Value.Group(), depth+1)
+				if len(children) == 0 {
+					continue
+				}
+				a.Value = slog.GroupValue(children...)
+				out = append(out, a)
+				continue
+			}
+		} else if v, ok := lim.truncateValue(a.Value); ok {
+			a.Value = v
+		}
+		if lim.budget > 0 {
+			lim.budget--
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// truncateValue 截断超过 MaxValueBytes 的值：字符串与 []byte 直接截取，
+// 其他 KindAny 值（结构体、error、map 等）按渲染后的文本判断并替换为截断后的字符串。
+func (lim *recordLimiter) truncateValue(v slog.Value) (slog.Value, bool) {
+	limit := lim.cfg.MaxValueBytes
+	if limit <= 0 {
+		return v, false
+	}
+	var s string
+	switch v.Kind() {
+	case slog.KindString:
+		s = v.String()
+	case slog.KindAny:
+		if b, ok := v.Any().([]byte); ok {
+			if len(b) <= limit {
+				return v, false
+			}
+			s = string(b[:limit+1])
+		} else {
+			s = renderAnyValue(v.Any())
+		}
+	default:
+		return v, false
+	}
+	if len(s) <= limit {
+		return v, false
+	}
+	lim.stats.Values++
+	return slog.StringValue(truncateUTF8(s, limit) + lim.marker), true
+}
+
+// boundContextFields 对上下文字段应用 MaxValueBytes，有值被截断时返回携带字段副本的 ctx。
+func (lim *recordLimiter) boundContextFields(ctx context.Context) context.Context {
+	fields := getFields(ctx)
+	if lim.cfg.MaxValueBytes <= 0 || fields == nil {
+		return ctx
+	}
+	var truncated map[string]slog.Value
+	fields.mu.RLock()
+	for key, val := range fields.values {
+		if v, ok := lim.truncateValue(slog.AnyValue(val).Resolve()); ok {
+			if truncated == nil {
+				truncated = make(map[string]slog.Value)
+			}
+			truncated[key] = v
+		}
+	}
+	fields.mu.RUnlock()
+	if truncated == nil {
+		return ctx
+	}
+	bounded := fields.clone()
+	for key, v := range truncated {
+		bounded.values[key] = v
+	}
+	return context.WithValue(ctx, fieldsKey, bounded)
+}
+
+// shrink 从末尾丢弃属性直至记录估算大小不超过 MaxRecordBytes，仍超出时截断消息。
+// 有属性被丢弃时为 attrs_dropped 预留空间。
+func (lim *recordLimiter) shrink(msg string, attrs []slog.Attr) ([]slog.Attr, string) {
+	limit := lim.cfg.MaxRecordBytes
+	sizes := make([]int, len(attrs))
+	total := len(msg)
+	for i, a := range attrs {
+		if !isStacktraceAttr(a) {
+			sizes[i] = attrSize(a)
+			total += sizes[i]
+		}
+	}
+	reserve := 0
+	if lim.dropped > 0 || (total > limit && total > len(msg)) {
+		reserve = attrSize(slog.Int(AttrsDroppedKey, lim.dropped+len(attrs)))
+	}
+	if total+reserve <= limit {
+		return attrs, msg
+	}
+	lim.stats.Records++
+	limit -= reserve
+
+	for i := len(attrs) - 1; i >= 0 && total > limit; i-- {
+		if sizes[i] == 0 {
+			continue
+		}
+		total -= sizes[i]
+		lim.dropped++
+		attrs = append(attrs[:i], attrs[i+1:]...)
+	}
+	if total > limit {
+		keep := limit - (total - len(msg))
+		if keep < 0 {
+			keep = 0
+		}
+		msg = truncateUTF8(msg, keep) + lim.marker
+	}
+	return attrs, msg
+}
+
+// isStacktraceAttr 判断属性是否为 StacktraceLevel 采集的调用栈，按值类型识别，同名的用户属性仍受限制。
+func isStacktraceAttr(a slog.Attr) bool {
+	if a.Value.Kind() != slog.KindAny {
+		return false
+	}
+	_, ok := a.Value.Any().(Stacktrace)
+	return ok
+}
+
+// attrSize 估算已求值属性的输出大小（键 + 值），分组递归计算。
+func attrSize(a slog.Attr) int {
+	n := len(a.Key) + 1
+	switch a.Value.Kind() {
+	case slog.KindString:
+		n += len(a.Value.String())
+	case slog.KindGroup:
+		for _, child := range a.Value.Group() {
+			n += attrSize(child)
+		}
+	case slog.KindAny:
+		if b, ok := a.Value.Any().([]byte); ok {
+			n += len(b)
+		} else {
+			n += len(renderAnyValue(a.Value.Any()))
+		}
+	default:
+		n += len(a.Value.String())
+	}
+	return n
+}
+
+// renderAnyValue 返回 KindAny 值的文本形式，error 取 Error()。
+func renderAnyValue(v any) string {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	return fmt.Sprint(v)
+}
+
+// truncateUTF8 截取 s 的前 n 字节，并回退到完整的 UTF-8 字符边界。
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,211 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newLimitedJSONLogger(buf *bytes.Buffer, configure func(*Config)) *Logger {
+	resetForTest()
+	cfg := DefaultConfig()
+	cfg.SetEnableText(false)
+	cfg.SetEnableJSON(true)
+	configure(cfg)
+	return NewLoggerWithConfig(buf, cfg)
+}
+
+func TestSizeLimitsValueAttrsDepth(t *testing.T) {
+	ResetMetrics()
+	defer ResetMetrics()
+
+	records, cancel := Subscribe(1)
+	defer cancel()
+
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.MaxValueBytes = 4
+		c.TruncationMarker = "~"
+		c.MaxAttrs = 4 // 含 attrs_dropped
+		c.MaxGroupDepth = 1
+	})
+	logger.Info("limited",
+		"body", "héllo world",
+		"raw", []byte("abcdefgh"),
+		Group("outer", Group("inner", "k", "v")),
+		"extra1", 1, "extra2", 2,
+	)
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if payload["body"] != "hél~" || payload["raw"] != "abcd~" {
+		t.Fatalf("unexpected truncated values: %s", buf.String())
+	}
+	if outer, _ := payload["outer"].(map[string]any); outer["inner"] != "[max depth]" {
+		t.Fatalf("expected depth placeholder: %s", buf.String())
+	}
+	if _, ok := payload["extra1"]; ok || payload[AttrsDroppedKey] != float64(2) {
+		t.Fatalf("expected extra attrs to be dropped: %s", buf.String())
+	}
+
+	select {
+	case event := <-records:
+		if attrs := flattenRecordAttrs(event.Record); attrs["body"] != "hél~" {
+			t.Fatalf("subscribers must see bounded record: %v", attrs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected subscription event")
+	}
+
+	tr := GetMetricsSnapshot().Truncation
+	if tr.Values != 2 || tr.Attrs != 2 || tr.Groups != 1 || tr.Records != 0 {
+		t.Fatalf("unexpected truncation stats: %+v", tr)
+	}
+}
+
+func TestSizeLimitsRecordBytes(t *testing.T) {
+	ResetMetrics()
+	defer ResetMetrics()
+
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) { c.MaxRecordBytes = 40 })
+	logger.Info("short", "a", strings.Repeat("x", 10), "b", strings.Repeat("y", 50))
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if payload["a"] == nil || payload["b"] != nil || payload[AttrsDroppedKey] != float64(1) {
+		t.Fatalf("expected trailing attr to be dropped: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Info(strings.Repeat("m", 100))
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if msg := payload["msg"].(string); msg != strings.Repeat("m", 40)+DefaultTruncationMarker {
+		t.Fatalf("expected truncated message, got %q", msg)
+	}
+	if GetMetricsSnapshot().Truncation.Records != 2 {
+		t.Fatalf("expected two oversize records")
+	}
+}
+
+type limitTestPayload struct {
+	Name string
+	Tags []string
+}
+
+func TestSizeLimitsResolvedAndRenderedValues(t *testing.T) {
+	ResetMetrics()
+	defer ResetMetrics()
+
+	var calls atomic.Int32
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.MaxValueBytes = 8
+		c.TruncationMarker = "~"
+	}).WithValue("trace", strings.Repeat("t", 20))
+
+	logger.Info("limited",
+		Lazy("lazy", func() any {
+			calls.Add(1)
+			return strings.Repeat("z", 20)
+		}),
+		"err", errors.New("connection refused by peer"),
+		"obj", limitTestPayload{Name: "payload", Tags: []string{"a", "b"}},
+		"short", errors.New("eof"),
+	)
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	want := map[string]string{"lazy": "zzzzzzzz~", "err": "connecti~", "obj": "{payload~", "trace": "tttttttt~"}
+	for key, val := range want {
+		if payload[key] != val {
+			t.Fatalf("%s: got %v, want %q in %s", key, payload[key], val, buf.String())
+		}
+	}
+	if payload["short"] == nil {
+		t.Fatalf("short values must be kept: %s", buf.String())
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("lazy value resolved %d times", calls.Load())
+	}
+	if tr := GetMetricsSnapshot().Truncation; tr.Values != 4 {
+		t.Fatalf("unexpected truncation stats: %+v", tr)
+	}
+}
+
+func TestSizeLimitsSkipDisabledRecords(t *testing.T) {
+	ResetMetrics()
+	defer ResetMetrics()
+
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) {
+		c.MaxValueBytes = 1
+		c.MaxRecordBytes = 1
+	})
+	SetLevelInfo()
+	defer resetForTest()
+	logger.Debug("hidden", "k", "long value")
+	if buf.Len() != 0 {
+		t.Fatalf("debug record should be disabled: %s", buf.String())
+	}
+	if tr := GetMetricsSnapshot().Truncation; tr != (TruncationStats{}) {
+		t.Fatalf("disabled records must not be counted: %+v", tr)
+	}
+}
+
+// sprintCounter 统计被 fmt 渲染的次数。
+type sprintCounter struct{ calls *atomic.Int32 }
+
+func (c sprintCounter) String() string {
+	c.calls.Add(1)
+	return "counter"
+}
+
+func TestSizeLimitsReserveDroppedAttrAndStacktraceByType(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLimitedJSONLogger(&buf, func(c *Config) { c.MaxRecordBytes = 40 })
+	logger.Info("short", "a", strings.Repeat("x", 10), "stacktrace", strings.Repeat("s", 60))
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if _, ok := payload["stacktrace"]; ok || payload[AttrsDroppedKey] != float64(1) {
+		t.Fatalf("user attr named stacktrace must be limited: %s", buf.String())
+	}
+
+	// 估算大小（含 attrs_dropped）不超过 MaxRecordBytes
+	r := slog.NewRecord(time.Now(), LevelInfo, "short", 0)
+	r.AddAttrs(String("a", strings.Repeat("x", 10)), String("b", strings.Repeat("y", 20)), String("c", strings.Repeat("z", 20)))
+	logger.applySizeLimits(context.Background(), &r)
+	size := len(r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		size += attrSize(a)
+		return true
+	})
+	if size > 40 {
+		t.Fatalf("bounded record is %d bytes, limit 40", size)
+	}
+
+	var calls atomic.Int32
+	attrsOnly := newLimitedJSONLogger(&bytes.Buffer{}, func(c *Config) { c.MaxAttrs = 8 })
+	r.AddAttrs(Any("obj", sprintCounter{&calls}))
+	attrsOnly.applySizeLimits(context.Background(), &r)
+	if calls.Load() != 0 {
+		t.Fatalf("values must not be rendered without byte limits, got %d calls", calls.Load())
+	}
+}
@@ -8,13 +8,13 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
-	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/darkit/slog/modules"
 	gelfmod "github.com/darkit/slog/modules/output/gelf"
 	logfmtmod "github.com/darkit/slog/modules/output/logfmt"
 )
@@ -396,7 +396,7 @@ func NewLogfmtLogger(w io.Writer, opts *HandlerOptions) *Logger {
 		Writer:      w,
 		Level:       opts.Level,
 		AddSource:   opts.AddSource,
-		TimeFormat:  TimeFormat,
+		TimeFormat:  currentTimeFormat(),
 		ReplaceAttr: opts.ReplaceAttr,
 	})
 	logger.text = slog.New(newAddonsHandler(handler, ext))
@@ -484,8 +484,13 @@ func SetAttrFormatterOrder(order ...AttrFormatterRule) {
 	attrFormatterOrder.Store(copyOrder)
 }
 
-// NewOptions 创建新的处理程序选项。
+// NewOptions 创建新的处理程序选项，时间按调用时的全局 TimeFormat 格式化。
 func NewOptions(options *HandlerOptions) *HandlerOptions {
+	return newOptionsWithTime(options, currentTimeFormat(), nil)
+}
+
+// newOptionsWithTime 与 NewOptions 相同，但使用实例自己的时间格式与时区。
+func newOptionsWithTime(options *HandlerOptions, layout string, loc *time.Location) *HandlerOptions {
 	var opts slog.HandlerOptions
 	if options != nil {
 		opts = *options
@@ -499,7 +504,8 @@ func NewOptions(options *HandlerOptions) *HandlerOptions {
 		opts.AddSource = true
 	}
 
-	normalizer := newAttrFormatter(TimeFormat)
+	normalizer := newAttrFormatter(layout)
+	normalizer.location = loc
 	opts.ReplaceAttr = chainReplaceAttr(opts.ReplaceAttr, normalizer.replace)
 
 	return &opts
@@ -508,6 +514,7 @@ func NewOptions(options *HandlerOptions) *HandlerOptions {
 // attrFormatter 负责根据项目约定格式化特殊字段，支持递归 group 处理。
 type attrFormatter struct {
 	timeFormat string
+	location   *time.Location // nil 表示保持原时区
 	order      []AttrFormatterRule
 }
 
@@ -615,15 +622,14 @@ func (f attrFormatter) normalizeTime(a slog.Attr) slog.Attr {
 }
 
 func (f attrFormatter) formatTime(val slog.Value) (string, bool) {
-	switch val.Kind() {
-	case slog.KindTime:
-		return val.Time().Format(f.timeFormat), true
-	case slog.KindAny:
-		if t, ok := val.Any().(time.Time); ok {
-			return t.Format(f.timeFormat), true
-		}
+	t, ok := timeFromValue(val)
+	if !ok {
+		return "", false
+	}
+	if f.location != nil {
+		t = t.In(f.location)
 	}
-	return "", false
+	return t.Format(f.timeFormat), true
 }
 
 func sourceFromValue(val slog.Value) *slog.Source {
@@ -645,25 +651,6 @@ func levelFromValue(val slog.Value) (Level, bool) {
 	return level, ok
 }
 
-func levelJSONName(level Level) (string, bool) {
-	switch level {
-	case LevelInfo:
-		return "Info", true
-	case LevelDebug:
-		return "Debug", true
-	case LevelWarn:
-		return "Warn", true
-	case LevelError:
-		return "Error", true
-	case LevelTrace:
-		return "Trace", true
-	case LevelFatal:
-		return "Fatal", true
-	default:
-		return "", false
-	}
-}
-
 func chainReplaceAttr(first, second func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
 	switch {
 	case first == nil:
@@ -903,7 +890,7 @@ func SetLevelError() { levelVar.Set(LevelError) }
 func SetLevelFatal() { levelVar.Set(LevelFatal) }
 
 // SetLevel 动态更新日志级别
-// level 可以是数字(-8, -4, 0, 4, 8, 12)或字符串(trace, debug, info, warn, error, fatal)
+// level 可以是数字(-8, -4, 0, 4, 8, 12)或字符串(trace, debug, info, warn, error, fatal)，也可以是已注册的自定义级别
 func SetLevel(level any) error {
 	var newLevel Level
 
@@ -913,23 +900,12 @@ func SetLevel(level any) error {
 	case int:
 		newLevel = Level(v)
 	case string:
-		// 将字符串转换为Level
-		switch strings.ToLower(v) {
-		case "trace":
-			newLevel = LevelTrace
-		case "debug":
-			newLevel = LevelDebug
-		case "info":
-			newLevel = LevelInfo
-		case "warn":
-			newLevel = LevelWarn
-		case "error":
-			newLevel = LevelError
-		case "fatal":
-			newLevel = LevelFatal
-		default:
+		// 将字符串转换为Level（包含 RegisterLevel 注册的自定义级别）
+		parsed, ok := modules.ParseLevel(v)
+		if !ok {
 			return errors.New("invalid log level string")
 		}
+		newLevel = parsed
 	default:
 		return errors.New("unsupported level type")
 	}
@@ -944,15 +920,38 @@ func SetLevel(level any) error {
 	return nil
 }
 
-// SetTimeFormat 全局方法：设置日志时间格式
+// SetTimeFormat 全局方法：设置之后新建的 Logger / handler 的默认时间格式，已创建的实例不受影响。
+// 需要按实例区分时使用 Config.TimeFormat / TimeZone 或 WithTimeFormat 等控制台选项。
 //
 //   - format: 时间格式字符串，例如 "2006-01-02 15:04:05.000"
 func SetTimeFormat(format string) {
 	if format != "" {
-		TimeFormat = format
+		timeFormatOverride.Store(&timeFormatSetting{format: format, base: TimeFormat})
 	}
 }
 
+// GetTimeFormat 返回当前全局默认时间格式。
+func GetTimeFormat() string {
+	return currentTimeFormat()
+}
+
+// timeFormatSetting 记录 SetTimeFormat 设置的格式以及设置时 TimeFormat 变量的值。
+type timeFormatSetting struct {
+	format string
+	base   string
+}
+
+// timeFormatOverride 保存 SetTimeFormat 设置的格式，可与日志写入并发读写。
+var timeFormatOverride atomic.Pointer[timeFormatSetting]
+
+// currentTimeFormat 返回最后一次设置的格式：SetTimeFormat 之后又直接给 TimeFormat 赋值时以变量为准。
+func currentTimeFormat() string {
+	if s := timeFormatOverride.Load(); s != nil && TimeFormat == s.base {
+		return s.format
+	}
+	return TimeFormat
+}
+
 // ResetGlobalLogger 重置全局logger实例
 // 这在某些情况下很有用，比如需要更改全局logger的输出目标
 func ResetGlobalLogger(w io.Writer, noColor, addSource bool) *Logger {
@@ -1124,17 +1123,3 @@ func DisableDLPLogger() {
 func IsDLPEnabled() bool {
 	return dlpEnabled.Load()
 }
-
-// isValidLevel 检查日志级别是否有效
-func isValidLevel(level Level) bool {
-	validLevels := []Level{
-		LevelTrace, // -8
-		LevelDebug, // -4
-		LevelInfo,  // 0
-		LevelWarn,  // 4
-		LevelError, // 8
-		LevelFatal, // 12
-	}
-
-	return slices.Contains(validLevels, level)
-}
@@ -26,7 +26,11 @@ const (
 )
 
 var (
-	TimeFormat = "2006/01/02 15:04.05.000" // 默认时间格式
+	// TimeFormat 默认时间格式。直接赋值仍然生效（与之后的 SetTimeFormat 以最后一次设置为准），但不是并发安全的；
+	// SetTimeFormat 不再回写该变量，读取当前格式请使用 GetTimeFormat。
+	//
+	// Deprecated: 运行期修改使用 SetTimeFormat，读取使用 GetTimeFormat。
+	TimeFormat = "2006/01/02 15:04.05.000"
 
 	callerSkipPrefixesMu    sync.RWMutex
 	callerSkipPrefixes      []string
@@ -40,16 +44,6 @@ var (
 		},
 	}
 
-	// 日志级别对应的TXT名称映射
-	levelTextNames = map[slog.Leveler]string{
-		LevelInfo:  "I",
-		LevelDebug: "D",
-		LevelWarn:  "W",
-		LevelError: "E",
-		LevelTrace: "T",
-		LevelFatal: "F",
-	}
-
 	// 日志格式字符串缓存，存储常用的格式字符串检测结果
 	// 键是格式字符串，值是布尔结果(是否包含格式说明符)
 	formatCache        *common.LRUStringCache
@@ -109,6 +103,11 @@ func DefaultCallerSkipPrefixes() []string {
 		"github.com/darkit/slog.WarnfContext",
 		"github.com/darkit/slog.ErrorfContext",
 		"github.com/darkit/slog.TracefContext",
+		"github.com/darkit/slog.TraceT",
+		"github.com/darkit/slog.DebugT",
+		"github.com/darkit/slog.InfoT",
+		"github.com/darkit/slog.WarnT",
+		"github.com/darkit/slog.ErrorT",
 	}
 }
 
@@ -244,8 +243,34 @@ type Config struct {
 	NoColor    bool  // 禁用颜色
 	AddSource  bool  // 添加源代码位置
 
-	// 时间配置
-	TimeFormat string // 时间格式
+	// 时间配置（仅作用于本实例，不受之后的 SetTimeFormat 影响）
+	TimeFormat   string         // 时间格式，为空时使用创建 Logger 时的全局 TimeFormat
+	TimeZone     *time.Location // 输出前转换到该时区，nil 表示保持记录时间的时区
+	RelativeTime bool           // 控制台输出距 Logger 创建的相对时间（如 "+1.203s"），JSON 仍为绝对时间
+
+	// 控制台输出选项（主题、着色模式等）
+	ConsoleOptions []ConsoleOption
+
+	// JSON 输出结构（nil 表示默认的 time/level/msg/source）
+	JSONSchema *JSONSchema
+
+	// 调用栈配置
+	StacktraceLevel Leveler // 不低于该级别的记录附加调用栈（nil 表示关闭）
+	StacktraceDepth int     // 调用栈最大帧数，<=0 时使用默认值 32
+
+	// panic 捕获配置
+	PanicLevel Leveler // Recover / Go / HTTPMiddleware 的记录级别（nil 表示 Error）
+	RePanic    bool    // 记录后重新抛出 panic
+
+	// 尺寸限制（0 表示不限制），在分发到各输出前生效
+	MaxValueBytes    int    // 单个值（求值后，其他类型按渲染文本）的最大字节数，超出部分以 TruncationMarker 替换
+	MaxAttrs         int    // 单条记录保留的最大属性数（分组内按叶子属性计）
+	MaxGroupDepth    int    // 分组最大嵌套层数，更深的分组替换为 "[max depth]"
+	MaxRecordBytes   int    // 消息与属性的估算总字节数上限，超出时从末尾丢弃属性
+	TruncationMarker string // 截断标记，为空时使用 DefaultTruncationMarker
+
+	// 重复键策略（nil 表示跟随全局 SetDuplicateKeyPolicy）
+	DuplicateKeys *DuplicateKeyPolicy
 }
 
 // DefaultConfig 返回默认配置
@@ -256,8 +281,31 @@ func DefaultConfig() *Config {
 		LogInternalErrors:     true,
 		NoColor:               false,
 		AddSource:             false,
-		TimeFormat:            "2006/01/02 15:04.05.000",
+		TimeFormat:            currentTimeFormat(),
+	}
+}
+
+// Validate 检查配置中无法生效的选项，目前包括未知的 JSONSchema.Profile。
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return c.JSONSchema.Validate()
+}
+
+// ConfigError 返回创建 Logger 时 Config 校验失败的原因，此时相应选项被忽略。
+func (l *Logger) ConfigError() error {
+	if l == nil {
+		return nil
 	}
+	return l.configErr
+}
+
+func (c *Config) timeLayout() string {
+	if c.TimeFormat != "" {
+		return c.TimeFormat
+	}
+	return currentTimeFormat()
 }
 
 func boolPtr(v bool) *bool {
@@ -299,6 +347,10 @@ func (c *Config) InheritJSONOutput() {
 type outputRenderConfig struct {
 	addSource   bool
 	replaceAttr func(groups []string, a slog.Attr) slog.Attr
+	jsonSchema  *JSONSchema     // 仅 JSON 渲染使用
+	console     []ConsoleOption // 仅文本渲染使用
+	timeLayout  string          // JSONSchema 的回退时间格式，为空时使用全局 TimeFormat
+	timeZone    *time.Location
 }
 
 // Logger 结构体定义，实现日志记录功能
@@ -313,6 +365,8 @@ type Logger struct {
 	mu           sync.Mutex         // 添加互斥锁，用于处理并发
 	config       *Config            // 配置信息
 	renderConfig outputRenderConfig // 渲染订阅语义化内容所需的配置快照
+	processors   []*processorEntry  // Logger 级处理器链，在全局处理器之后执行
+	configErr    error              // 创建时 Config 校验失败的原因
 }
 
 // GetLevel 获取当前日志级别
@@ -401,43 +455,96 @@ func (l *Logger) logfWithLevel(level Level, format string, args ...any) {
 	l.logRecord(level, l.ctx, fmt.Sprintf(format, args...), true, args...)
 }
 
+// recordMode 决定 args 如何进入记录
+type recordMode uint8
+
+const (
+	recordArgs     recordMode = iota // 按消息是否含格式说明符决定 Sprintf 或作为属性
+	recordSprintf                    // msg 已格式化，args 不再作为属性
+	recordVerbatim                   // msg 原样使用，args 全部作为属性（消息模板）
+)
+
 // logRecord 日志记录的核心实现
 // 处理所有类型的日志记录请求
 func (l *Logger) logRecord(level Level, ctx context.Context, msg string, sprintf bool, args ...any) {
+	mode := recordArgs
+	if sprintf {
+		mode = recordSprintf
+	}
+	l.emitRecord(level, ctx, msg, mode, args)
+}
+
+func (l *Logger) emitRecord(level Level, ctx context.Context, msg string, mode recordMode, args []any) {
 	if l == nil {
 		return
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
+
+	// 没有任何输出接受该级别且无订阅者时直接返回，丢弃规则、大小限制与调用栈采集都不执行；
+	// 注册了处理器时仍先执行处理器，处理器可以把被过滤的记录调整到已启用的级别。
+	textEnabledForInstance, jsonEnabledForInstance := l.outputEnabled()
+	processing := l.hasProcessors()
+	if !processing && !l.anyOutputEnabled(ctx, level, textEnabledForInstance, jsonEnabledForInstance) {
+		return
+	}
 	if globalRateLimiter != nil && !globalRateLimiter.Allow() {
+		limiterDropped.Add(1)
 		if l.config == nil || l.config.LogInternalErrors {
 			fmt.Fprintf(os.Stderr, "slog: record dropped by limiter level=%s msg=%q\n", level, msg)
 		}
 		return
 	}
 
-	textEnabledForInstance, jsonEnabledForInstance := l.outputEnabled()
+	originalLevel := level
 	recordPC := uintptr(0)
 	if l.needsCallerPC(textEnabledForInstance, jsonEnabledForInstance) {
 		recordPC = resolveCallerPC()
 	}
 
+	// 存在上下文字段或传播器时，重复键在 eHandler 合并上下文属性后统一处理，避免重复应用策略
+	policy, override := l.duplicateKeyPolicy()
+	if currentContextPropagator() != nil || hasContextFields(getFields(ctx)) {
+		if override {
+			ctx = withDuplicateKeyPolicy(ctx, policy)
+		}
+		policy = DuplicateKeysKeepAll
+	}
 	var r slog.Record
-	if sprintf {
+	if mode == recordSprintf {
 		r = newRecordWithPC(level, recordPC, msg)
-		appendBoundAttrs(&r, l.boundAttrs)
-	} else if formatLog(msg, args...) {
+		appendBoundAttrs(&r, policy, l.boundAttrs)
+	} else if mode == recordArgs && formatLog(msg, args...) {
 		r = newRecordWithPC(level, recordPC, msg, args...)
-		appendBoundAttrs(&r, l.boundAttrs)
+		appendBoundAttrs(&r, policy, l.boundAttrs)
 	} else {
 		r = newRecordWithPC(level, recordPC, msg)
-		appendBoundAttrs(&r, l.boundAttrs)
-		r.Add(args...)
+		appendBoundAttrs(&r, policy, l.boundAttrs, args...)
+	}
+
+	// 先绑定 Lazy 缓存，处理器、丢弃规则与各输出共享同一次求值。
+	bindLazyAttrs(&r)
+	if !l.runProcessors(ctx, &r) {
+		return
+	}
+	level = r.Level
+	if (processing || level != originalLevel) && !l.anyOutputEnabled(ctx, level, textEnabledForInstance, jsonEnabledForInstance) {
+		return
+	}
+	if l.matchDropRules(ctx, r) {
+		return
+	}
+	ctx = l.applySizeLimits(ctx, &r)
+	if stack := l.stacktraceFor(level); stack != nil && !recordHasStacktrace(r) {
+		r.AddAttrs(slog.Any(StacktraceKey, stack))
 	}
 
 	if textEnabledForInstance && l.text != nil && l.text.Enabled(ctx, level) {
-		if err := l.text.Handler().Handle(ctx, r); err != nil {
+		start := time.Now()
+		err := l.text.Handler().Handle(ctx, r)
+		observeOutput(metricsOutputText, level, start, err)
+		if err != nil {
 			// 记录内部错误到stderr，但不阻塞日志记录
 			if l.config == nil || l.config.LogInternalErrors {
 				fmt.Fprintf(os.Stderr, "slog: text handler error: %v\n", err)
@@ -445,7 +552,10 @@ func (l *Logger) logRecord(level Level, ctx context.Context, msg string, sprintf
 		}
 	}
 	if jsonEnabledForInstance && l.json != nil && l.json.Enabled(ctx, level) {
-		if err := l.json.Handler().Handle(ctx, r); err != nil {
+		start := time.Now()
+		err := l.json.Handler().Handle(ctx, r)
+		observeOutput(metricsOutputJSON, level, start, err)
+		if err != nil {
 			// 记录内部错误到stderr，但不阻塞日志记录
 			if l.config == nil || l.config.LogInternalErrors {
 				fmt.Fprintf(os.Stderr, "slog: json handler error: %v\n", err)
@@ -458,6 +568,7 @@ func (l *Logger) logRecord(level Level, ctx context.Context, msg string, sprintf
 		return
 	}
 
+	start := time.Now()
 	event := l.subscriptionEvent(ctx, r)
 	var toDelete []any
 
@@ -472,6 +583,7 @@ func (l *Logger) logRecord(level Level, ctx context.Context, msg string, sprintf
 
 		return true
 	})
+	observeOutput(metricsOutputSubscription, level, start, nil)
 
 	// 清理失活订阅者
 	for _, key := range toDelete {
@@ -485,6 +597,28 @@ func (l *Logger) logRecord(level Level, ctx context.Context, msg string, sprintf
 	}
 }
 
+// mayEmit 判断 level 的记录是否可能被输出，供调用方在构造消息前提前返回。
+// 注册了处理器时总是返回 true，处理器可能调整记录级别。
+func (l *Logger) mayEmit(ctx context.Context, level Level) bool {
+	if l == nil {
+		return false
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	textOn, jsonOn := l.outputEnabled()
+	return l.hasProcessors() || l.anyOutputEnabled(ctx, level, textOn, jsonOn)
+}
+
+// anyOutputEnabled 判断 text / json 输出是否接受 level，或存在订阅者。
+func (l *Logger) anyOutputEnabled(ctx context.Context, level Level, textOn, jsonOn bool) bool {
+	if subscriberCount.Load() > 0 {
+		return true
+	}
+	return (textOn && l.text != nil && l.text.Enabled(ctx, level)) ||
+		(jsonOn && l.json != nil && l.json.Enabled(ctx, level))
+}
+
 func (l *Logger) needsCallerPC(textOn, jsonOn bool) bool {
 	if l == nil || !l.renderConfig.addSource {
 		return false
@@ -731,6 +865,8 @@ func (l *Logger) clone() *Logger {
 		mu:           sync.Mutex{}, // 每个logger实例都有独立的互斥锁
 		config:       l.config,
 		renderConfig: l.renderConfig,
+		processors:   slices.Clone(l.processors),
+		configErr:    l.configErr,
 	}
 
 	return newLogger
@@ -854,11 +990,32 @@ func scanFormatSpecifiers(msg string) bool {
 	return false
 }
 
-func appendBoundAttrs(r *slog.Record, attrs []slog.Attr) {
-	if r == nil || len(attrs) == 0 {
+// appendBoundAttrs 将 With 绑定的属性与本次调用的 args 依次加入记录，并按 policy 处理同名键。
+func appendBoundAttrs(r *slog.Record, policy DuplicateKeyPolicy, attrs []slog.Attr, args ...any) {
+	if r == nil {
 		return
 	}
-	r.AddAttrs(attrs...)
+	if policy == DuplicateKeysKeepAll || len(attrs) == 0 && singleArgAttr(args) {
+		r.AddAttrs(attrs...)
+		r.Add(args...)
+		return
+	}
+	if len(args) > 0 {
+		attrs = append(slices.Clip(attrs), argsToAttrs(args)...)
+	}
+	r.AddAttrs(policy.Apply(attrs, false)...)
+}
+
+// singleArgAttr 判断 args 是否至多构成一个属性，此时不可能出现重复键。
+func singleArgAttr(args []any) bool {
+	switch len(args) {
+	case 0, 1:
+		return true
+	case 2:
+		_, ok := args[0].(string)
+		return ok
+	}
+	return false
 }
 
 func (l *Logger) materializedSlogLogger(base *slog.Logger) *slog.Logger {
@@ -874,7 +1031,8 @@ func NewLoggerWithConfig(w io.Writer, config *Config) *Logger {
 		config = DefaultConfig()
 	}
 
-	options := NewOptions(nil)
+	layout := config.timeLayout()
+	options := newOptionsWithTime(nil, layout, config.TimeZone)
 	options.AddSource = config.AddSource || levelVar.Level() < LevelDebug
 
 	// 如果需要DLP,则初始化
@@ -886,6 +1044,12 @@ func NewLoggerWithConfig(w io.Writer, config *Config) *Logger {
 		w = NewWriter()
 	}
 
+	console := []ConsoleOption{WithTimeFormat(layout), WithTimeZone(config.TimeZone)}
+	if config.RelativeTime {
+		console = append(console, WithRelativeTime(time.Now()))
+	}
+	console = append(console, config.ConsoleOptions...)
+
 	newLogger := &Logger{
 		w:            w,
 		noColor:      config.NoColor,
@@ -893,9 +1057,19 @@ func NewLoggerWithConfig(w io.Writer, config *Config) *Logger {
 		ctx:          context.Background(),
 		config:       config,
 		renderConfig: newOutputRenderConfig(options),
-		text:         slog.New(newAddonsHandler(NewConsoleHandler(w, config.NoColor, options), ext)),
-		json:         slog.New(newAddonsHandler(NewJSONHandler(w, options), ext)),
+		text:         slog.New(newAddonsHandler(NewConsoleHandler(w, config.NoColor, options, console...), ext)),
+	}
+	newLogger.renderConfig.console = console
+	newLogger.renderConfig.timeLayout = layout
+	newLogger.renderConfig.timeZone = config.TimeZone
+	if err := config.Validate(); err != nil {
+		newLogger.configErr = err
+		if config.LogInternalErrors {
+			fmt.Fprintf(os.Stderr, "slog: %v\n", err)
+		}
 	}
+	newLogger.renderConfig.jsonSchema = config.JSONSchema
+	newLogger.json = slog.New(newAddonsHandler(config.JSONSchema.newJSONHandler(w, options, layout, config.TimeZone), ext))
 
 	return newLogger
 }
@@ -253,17 +253,29 @@ func (e *extensions) emitDiagnostics(stage string, groups []string, before, afte
 	if e == nil || !e.diagnostics.Load() || !attrChanged(before, after) {
 		return
 	}
+	if w := e.diagnosticsOutput(); w != nil {
+		fmt.Fprintf(w, "[slog-diagnostics] stage=%s groups=%v key=%s before=%s after=%s\n", stage, groups, after.Key, before.Value, after.Value)
+	}
+}
+
+// emitStageDiagnostics 输出记录级（非单个属性）阶段的诊断信息。
+func (e *extensions) emitStageDiagnostics(stage, detail string) {
+	if e == nil || !e.diagnostics.Load() {
+		return
+	}
+	if w := e.diagnosticsOutput(); w != nil {
+		fmt.Fprintf(w, "[slog-diagnostics] stage=%s %s\n", stage, detail)
+	}
+}
+
+func (e *extensions) diagnosticsOutput() io.Writer {
 	writerPtr := e.diagnosticsWriter.Load()
 	if writerPtr == nil {
 		w := io.Writer(os.Stderr)
 		e.diagnosticsWriter.Store(&w)
 		writerPtr = &w
 	}
-	w := *writerPtr
-	if w == nil {
-		return
-	}
-	fmt.Fprintf(w, "[slog-diagnostics] stage=%s groups=%v key=%s before=%s after=%s\n", stage, groups, after.Key, before.Value, after.Value)
+	return *writerPtr
 }
 
 func attrChanged(before, after slog.Attr) bool {
@@ -350,7 +362,13 @@ func cloneHandlerWithContext(handler slog.Handler, ctx context.Context) slog.Han
 }
 
 func (h *eHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return h.handler.Enabled(ctx, level)
+	if h.handler.Enabled(ctx, level) {
+		return true
+	}
+	if ctx == nil {
+		ctx = h.ctx
+	}
+	return levelOverrideEnables(ctx, level)
 }
 
 // Handle 处理日志记录，如果需要，将前缀添加到消息，并将记录传递给下一个处理器。
@@ -407,13 +425,15 @@ func (h *eHandler) transformAttrs(groups []string, attrs []slog.Attr) []slog.Att
 }
 
 func (h *eHandler) transformAttr(groups []string, attr slog.Attr) slog.Attr {
-	// 先处理LogValuer
-	for attr.Value.Kind() == slog.KindLogValuer {
-		attr.Value = attr.Value.LogValuer().LogValue()
+	// 先处理LogValuer；仅在开启错误展开时，实现了 LogValuer 的 error 留给展开处理
+	if !skipErrorLogValuer(attr.Value) {
+		for attr.Value.Kind() == slog.KindLogValuer {
+			attr.Value = attr.Value.LogValuer().LogValue()
+		}
 	}
 
 	if h.opts == nil {
-		return attr
+		return renderErrors(attr)
 	}
 
 	// 应用所有formatters
@@ -421,6 +441,9 @@ func (h *eHandler) transformAttr(groups []string, attr slog.Attr) slog.Attr {
 	attr = h.opts.applyFormatters(groups, attr)
 	h.opts.emitDiagnostics("formatter", groups, before, attr)
 
+	// 结构化展开 error（位于 formatter 之后，ErrorFormatter 等自定义格式优先）
+	attr = renderErrors(attr)
+
 	// DLP处理
 	if h.opts.dlpEnabled.Load() && h.opts.dlpEngine != nil {
 		before := attr
@@ -441,6 +464,16 @@ func (h *eHandler) transformAttr(groups []string, attr slog.Attr) slog.Attr {
 	return attr
 }
 
+// skipErrorLogValuer 判断是否跳过 LogValuer 解析：结构化错误展开关闭时必须解析，
+// 否则 DLP 看不到 LogValue 返回的内容。
+func skipErrorLogValuer(v slog.Value) bool {
+	if _, enabled := errorRenderDepth(); !enabled {
+		return false
+	}
+	_, isErr := errorFromValue(v)
+	return isErr
+}
+
 func desensitizeAttrValue(engine *dlp.DlpEngine, key string, value string) string {
 	if engine == nil || value == "" {
 		return value
@@ -477,7 +510,7 @@ func (h *eHandler) prepareRecord(ctx context.Context, r slog.Record) slog.Record
 	if len(h.observerOps) > 0 {
 		return h.normalizedObserverRecord(ctx, r)
 	}
-	if h.canPassThrough(ctx) {
+	if h.canPassThrough(ctx) && !recordHasErrorStructure(r) {
 		return r
 	}
 	return h.normalizeRuntimeRecord(ctx, r)
@@ -561,20 +594,30 @@ func (h *eHandler) runtimeAttrs(ctx context.Context, r slog.Record, groups []str
 		return h.appendRecordAttrs(nil, r, groups)
 	}
 
+	policy := contextDuplicateKeyPolicy(ctx)
 	attrs := make([]slog.Attr, 0, 8)
 	if hasContextFields(fields) {
-		seen := make(map[string]struct{}, 8)
-		r.Attrs(func(attr slog.Attr) bool {
-			seen[attr.Key] = struct{}{}
-			return true
-		})
+		// 默认策略下保持原有行为：记录中已有的键不再追加同名上下文字段。
+		var seen map[string]struct{}
+		if policy == DuplicateKeysKeepAll {
+			seen = make(map[string]struct{}, 8)
+			r.Attrs(func(attr slog.Attr) bool {
+				seen[attr.Key] = struct{}{}
+				return true
+			})
+		}
 
 		fields.mu.RLock()
-		for key, val := range fields.values {
+		keys := make([]string, 0, len(fields.values))
+		for key := range fields.values {
 			if _, exists := seen[key]; !exists && key != "$module" {
-				attrs = append(attrs, h.transformAttr(groups, slog.Any(key, val)))
+				keys = append(keys, key)
 			}
 		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			attrs = append(attrs, h.transformAttr(groups, slog.Any(key, fields.values[key])))
+		}
 		fields.mu.RUnlock()
 	}
 
@@ -586,7 +629,8 @@ func (h *eHandler) runtimeAttrs(ctx context.Context, r slog.Record, groups []str
 		}
 	}
 
-	return h.appendRecordAttrs(attrs, r, groups)
+	// 上下文字段在前、记录属性在后，按重复键策略合并
+	return policy.Apply(h.appendRecordAttrs(attrs, r, groups), false)
 }
 
 func hasContextFields(fields *Fields) bool {
@@ -0,0 +1,469 @@
+package slog
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/darkit/slog/internal/common"
+	"github.com/darkit/slog/modules"
+)
+
+const (
+	metricsOutputText         = "text"
+	metricsOutputJSON         = "json"
+	metricsOutputSubscription = "subscription"
+)
+
+// writeLatencyBuckets 写入耗时直方图的上界（秒）。
+var writeLatencyBuckets = []float64{
+	0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1,
+}
+
+var (
+	pipelineOutputs sync.Map // map[string]*outputMetrics
+	limiterDropped  atomic.Uint64
+)
+
+// outputMetrics 记录单个输出通道的记录数、错误数与写入耗时。
+type outputMetrics struct {
+	records sync.Map // map[Level]*atomic.Uint64
+	errors  atomic.Uint64
+	buckets []atomic.Uint64
+	count   atomic.Uint64
+	sumNs   atomic.Uint64
+}
+
+func outputMetricsFor(output string) *outputMetrics {
+	if v, ok := pipelineOutputs.Load(output); ok {
+		return v.(*outputMetrics)
+	}
+	v, _ := pipelineOutputs.LoadOrStore(output, &outputMetrics{
+		buckets: make([]atomic.Uint64, len(writeLatencyBuckets)),
+	})
+	return v.(*outputMetrics)
+}
+
+func (m *outputMetrics) observe(level Level, elapsed time.Duration, err error) {
+	counter, ok := m.records.Load(level)
+	if !ok {
+		counter, _ = m.records.LoadOrStore(level, new(atomic.Uint64))
+	}
+	counter.(*atomic.Uint64).Add(1)
+	if err != nil {
+		m.errors.Add(1)
+	}
+
+	seconds := elapsed.Seconds()
+	for i, bound := range writeLatencyBuckets {
+		if seconds <= bound {
+			m.buckets[i].Add(1)
+			break
+		}
+	}
+	m.count.Add(1)
+	if elapsed > 0 {
+		m.sumNs.Add(uint64(elapsed))
+	}
+}
+
+func observeOutput(output string, level Level, start time.Time, err error) {
+	outputMetricsFor(output).observe(level, time.Since(start), err)
+}
+
+// LatencyHistogram 描述写入耗时分布，Counts 为累计计数，与 Buckets 一一对应。
+type LatencyHistogram struct {
+	Buckets []float64 `json:"buckets"`
+	Counts  []uint64  `json:"counts"`
+	Count   uint64    `json:"count"`
+	Sum     float64   `json:"sum"`
+}
+
+// PoolMetrics 描述分级对象池单个层级的统计。
+type PoolMetrics struct {
+	Gets     int64   `json:"gets"`
+	Puts     int64   `json:"puts"`
+	News     int64   `json:"news"`
+	Discards int64   `json:"discards"`
+	HitRate  float64 `json:"hit_rate"`
+}
+
+// MetricsSnapshot 汇总日志管线的全部运行指标。
+type MetricsSnapshot struct {
+	// Records 按输出、级别统计的记录数：output -> level -> count。
+	Records map[string]map[string]uint64 `json:"records"`
+	// HandlerErrors 按输出统计的 handler 错误数。
+	HandlerErrors map[string]uint64 `json:"handler_errors"`
+	// WriteLatency 按输出统计的写入耗时直方图。
+	WriteLatency map[string]LatencyHistogram `json:"write_latency"`
+	// LimiterDropped 被全局限流器丢弃的记录数。
+	LimiterDropped uint64 `json:"limiter_dropped"`
+	// Subscriptions 订阅系统统计。
+	Subscriptions SubscriptionStats `json:"subscriptions"`
+	// AsyncDropped 模块异步执行器因队列满丢弃的任务数。
+	AsyncDropped uint64 `json:"async_dropped"`
+	// DLPCacheHits / DLPCacheMisses DLP 结果缓存命中统计。
+	DLPCacheHits   int64 `json:"dlp_cache_hits"`
+	DLPCacheMisses int64 `json:"dlp_cache_misses"`
+	// Manager LoggerManager 实例统计。
+	Manager ManagerStats `json:"manager"`
+	// Modules 已注册模块的健康与指标。
+	Modules []ModuleDiagnostics `json:"modules"`
+	// Pools 分级对象池统计：small / medium / large。
+	Pools map[string]PoolMetrics `json:"pools"`
+	// Processors 全局处理器执行统计。
+	Processors []ProcessorDiagnostics `json:"processors"`
+	// DropRules 丢弃规则状态与命中次数。
+	DropRules []DropRuleStats `json:"drop_rules"`
+	// Truncation 尺寸限制触发统计。
+	Truncation TruncationStats `json:"truncation"`
+}
+
+// GetMetricsSnapshot 返回当前日志管线指标快照。
+func GetMetricsSnapshot() MetricsSnapshot {
+	snap := MetricsSnapshot{
+		Records:        make(map[string]map[string]uint64),
+		HandlerErrors:  make(map[string]uint64),
+		WriteLatency:   make(map[string]LatencyHistogram),
+		LimiterDropped: limiterDropped.Load(),
+		Subscriptions:  GetSubscriptionStats(),
+		AsyncDropped:   modules.GetAsyncDropCount(),
+		Manager:        globalManager.GetStats(),
+		Modules:        CollectModuleDiagnostics(),
+		Pools:          make(map[string]PoolMetrics),
+		Processors:     CollectProcessorDiagnostics(),
+		DropRules:      GetDropRuleStats(),
+		Truncation:     getTruncationStats(),
+	}
+
+	pipelineOutputs.Range(func(key, value any) bool {
+		output := key.(string)
+		m := value.(*outputMetrics)
+		levels := make(map[string]uint64)
+		m.records.Range(func(k, v any) bool {
+			levels[metricsLevelLabel(k.(Level))] += v.(*atomic.Uint64).Load()
+			return true
+		})
+		snap.Records[output] = levels
+		snap.HandlerErrors[output] = m.errors.Load()
+		snap.WriteLatency[output] = m.histogram()
+		return true
+	})
+
+	if ext != nil && ext.dlpEngine != nil {
+		snap.DLPCacheHits, snap.DLPCacheMisses = ext.dlpEngine.GetCacheStats()
+	}
+
+	for size, stats := range common.GlobalTieredPools.GetStats() {
+		snap.Pools[poolTierName(size)] = PoolMetrics{
+			Gets:     stats.Gets,
+			Puts:     stats.Puts,
+			News:     stats.News,
+			Discards: stats.Discards,
+			HitRate:  stats.HitRate,
+		}
+	}
+	return snap
+}
+
+func (m *outputMetrics) histogram() LatencyHistogram {
+	h := LatencyHistogram{
+		Buckets: append([]float64(nil), writeLatencyBuckets...),
+		Counts:  make([]uint64, len(writeLatencyBuckets)),
+		Count:   m.count.Load(),
+		Sum:     float64(m.sumNs.Load()) / float64(time.Second),
+	}
+	var cumulative uint64
+	for i := range writeLatencyBuckets {
+		cumulative += m.buckets[i].Load()
+		h.Counts[i] = cumulative
+	}
+	return h
+}
+
+// ResetMetrics 清空管线计数器（记录数、错误、耗时、限流丢弃与截断统计），便于测试或周期性采样。
+func ResetMetrics() {
+	pipelineOutputs.Range(func(key, _ any) bool {
+		pipelineOutputs.Delete(key)
+		return true
+	})
+	limiterDropped.Store(0)
+	resetTruncationStats()
+}
+
+func metricsLevelLabel(level Level) string {
+	if name, ok := levelJSONName(level); ok {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(level.String())
+}
+
+func poolTierName(size common.BufferSize) string {
+	switch size {
+	case common.SmallBuffer:
+		return "small"
+	case common.MediumBuffer:
+		return "medium"
+	case common.LargeBuffer:
+		return "large"
+	default:
+		return strconv.Itoa(int(size))
+	}
+}
+
+// MetricsHandler 返回以 Prometheus 文本格式暴露指标的 http.Handler。
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := WriteMetrics(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+var expvarMu sync.Mutex
+
+// PublishMetricsExpvar 以 name（为空时为 "slog"）在 expvar 中发布指标快照；重复发布同名变量会被忽略。
+func PublishMetricsExpvar(name string) {
+	if name == "" {
+		name = "slog"
+	}
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvar.Get(name) != nil {
+		return
+	}
+	expvar.Publish(name, expvar.Func(func() any {
+		return GetMetricsSnapshot()
+	}))
+}
+
+// WriteMetrics 将指标快照以 Prometheus 文本格式写入 w。
+func WriteMetrics(w io.Writer) error {
+	snap := GetMetricsSnapshot()
+	pw := &promWriter{w: bufio.NewWriter(w)}
+
+	pw.family("slog_records_total", "Records handled per output and level.", "counter")
+	for _, output := range sortedKeys(snap.Records) {
+		levels := snap.Records[output]
+		for _, level := range sortedKeys(levels) {
+			pw.sample("slog_records_total", promLabels{"output", output, "level", level}, float64(levels[level]))
+		}
+	}
+
+	pw.family("slog_handler_errors_total", "Errors returned by output handlers.", "counter")
+	for _, output := range sortedKeys(snap.HandlerErrors) {
+		pw.sample("slog_handler_errors_total", promLabels{"output", output}, float64(snap.HandlerErrors[output]))
+	}
+
+	pw.family("slog_write_duration_seconds", "Time spent handing a record to each output.", "histogram")
+	for _, output := range sortedKeys(snap.WriteLatency) {
+		h := snap.WriteLatency[output]
+		for i, bound := range h.Buckets {
+			pw.sample("slog_write_duration_seconds_bucket", promLabels{"output", output, "le", formatPromFloat(bound)}, float64(h.Counts[i]))
+		}
+		pw.sample("slog_write_duration_seconds_bucket", promLabels{"output", output, "le", "+Inf"}, float64(h.Count))
+		pw.sample("slog_write_duration_seconds_sum", promLabels{"output", output}, h.Sum)
+		pw.sample("slog_write_duration_seconds_count", promLabels{"output", output}, float64(h.Count))
+	}
+
+	pw.family("slog_records_dropped_total", "Records dropped before reaching any output.", "counter")
+	pw.sample("slog_records_dropped_total", promLabels{"reason", "limiter"}, float64(snap.LimiterDropped))
+
+	pw.family("slog_truncations_total", "Size limit enforcements applied before fanout.", "counter")
+	pw.sample("slog_truncations_total", promLabels{"kind", "value"}, float64(snap.Truncation.Values))
+	pw.sample("slog_truncations_total", promLabels{"kind", "attr"}, float64(snap.Truncation.Attrs))
+	pw.sample("slog_truncations_total", promLabels{"kind", "group"}, float64(snap.Truncation.Groups))
+	pw.sample("slog_truncations_total", promLabels{"kind", "record"}, float64(snap.Truncation.Records))
+
+	sub := snap.Subscriptions
+	pw.family("slog_subscribers", "Current subscribers by state.", "gauge")
+	pw.sample("slog_subscribers", promLabels{"state", "active"}, float64(sub.ActiveSubscribers))
+	pw.sample("slog_subscribers", promLabels{"state", "closing"}, float64(sub.ClosingSubscribers))
+	pw.sample("slog_subscribers", promLabels{"state", "closed"}, float64(sub.ClosedSubscribers))
+	pw.family("slog_subscription_published_total", "Events published to live subscribers.", "counter")
+	pw.sample("slog_subscription_published_total", nil, float64(sub.Published))
+	pw.family("slog_subscription_delivered_total", "Events delivered to live subscribers.", "counter")
+	pw.sample("slog_subscription_delivered_total", nil, float64(sub.Delivered))
+	pw.family("slog_subscription_dropped_total", "Events dropped by subscriber backpressure.", "counter")
+	pw.sample("slog_subscription_dropped_total", promLabels{"policy", string(SubscriptionDropOldest)}, float64(sub.DroppedOldest))
+	pw.sample("slog_subscription_dropped_total", promLabels{"policy", string(SubscriptionDropNewest)}, float64(sub.DroppedNewest))
+	pw.sample("slog_subscription_dropped_total", promLabels{"policy", string(SubscriptionBlockWithTimeout)}, float64(sub.DroppedTimed))
+	pw.family("slog_subscription_evicted_total", "Subscribers evicted after becoming inactive.", "counter")
+	pw.sample("slog_subscription_evicted_total", nil, float64(sub.Evicted))
+
+	pw.family("slog_async_tasks_dropped_total", "Module async tasks dropped because the queue was full.", "counter")
+	pw.sample("slog_async_tasks_dropped_total", nil, float64(snap.AsyncDropped))
+
+	pw.family("slog_dlp_cache_requests_total", "DLP result cache lookups.", "counter")
+	pw.sample("slog_dlp_cache_requests_total", promLabels{"result", "hit"}, float64(snap.DLPCacheHits))
+	pw.sample("slog_dlp_cache_requests_total", promLabels{"result", "miss"}, float64(snap.DLPCacheMisses))
+
+	pw.family("slog_manager_instances", "Named logger instances held by the manager.", "gauge")
+	pw.sample("slog_manager_instances", nil, float64(snap.Manager.InstanceCount))
+	pw.family("slog_manager_default_logger", "Whether the default logger has been created.", "gauge")
+	pw.sample("slog_manager_default_logger", nil, promBool(snap.Manager.DefaultLoggerExists))
+
+	pw.family("slog_module_enabled", "Whether a registered module is enabled.", "gauge")
+	for _, m := range snap.Modules {
+		pw.sample("slog_module_enabled", promLabels{"module", m.Name}, promBool(m.Enabled))
+	}
+	pw.family("slog_module_healthy", "Health check result of a registered module.", "gauge")
+	for _, m := range snap.Modules {
+		if m.Healthy != nil {
+			pw.sample("slog_module_healthy", promLabels{"module", m.Name}, promBool(*m.Healthy))
+		}
+	}
+	pw.family("slog_module_metric", "Numeric metrics reported by modules via GetMetrics.", "gauge")
+	for _, m := range snap.Modules {
+		for _, key := range sortedKeys(m.Metrics) {
+			if v, ok := promNumber(m.Metrics[key]); ok {
+				pw.sample("slog_module_metric", promLabels{"module", m.Name, "metric", key}, v)
+			}
+		}
+	}
+
+	pw.family("slog_pool_operations_total", "Tiered buffer pool operations.", "counter")
+	for _, tier := range sortedKeys(snap.Pools) {
+		p := snap.Pools[tier]
+		pw.sample("slog_pool_operations_total", promLabels{"tier", tier, "op", "get"}, float64(p.Gets))
+		pw.sample("slog_pool_operations_total", promLabels{"tier", tier, "op", "put"}, float64(p.Puts))
+		pw.sample("slog_pool_operations_total", promLabels{"tier", tier, "op", "new"}, float64(p.News))
+		pw.sample("slog_pool_operations_total", promLabels{"tier", tier, "op", "discard"}, float64(p.Discards))
+	}
+
+	pw.family("slog_drop_rule_hits_total", "Records dropped by each declarative drop rule.", "counter")
+	for _, rule := range snap.DropRules {
+		pw.sample("slog_drop_rule_hits_total", promLabels{"rule", rule.Name}, float64(rule.Hits))
+	}
+
+	pw.family("slog_processor_records_total", "Records seen by global processors by result.", "counter")
+	for _, p := range snap.Processors {
+		pw.sample("slog_processor_records_total", promLabels{"processor", p.Name, "id", p.ID, "result", "processed"}, float64(p.Calls))
+		pw.sample("slog_processor_records_total", promLabels{"processor", p.Name, "id", p.ID, "result", "dropped"}, float64(p.Dropped))
+		pw.sample("slog_processor_records_total", promLabels{"processor", p.Name, "id", p.ID, "result", "panic"}, float64(p.Panics))
+	}
+
+	return pw.flush()
+}
+
+// promLabels 以 name, value 交替排列的标签对。
+type promLabels []string
+
+type promWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (p *promWriter) family(name, help, typ string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (p *promWriter) sample(name string, labels promLabels, value float64) {
+	if p.err != nil {
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(name)
+	if len(labels) > 0 {
+		sb.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(labels[i])
+			sb.WriteString(`="`)
+			sb.WriteString(escapePromLabel(labels[i+1]))
+			sb.WriteByte('"')
+		}
+		sb.WriteByte('}')
+	}
+	sb.WriteByte(' ')
+	sb.WriteString(formatPromFloat(value))
+	sb.WriteByte('\n')
+	_, p.err = p.w.WriteString(sb.String())
+}
+
+func (p *promWriter) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+func (p *promWriter) flush() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
+
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapePromLabel(v string) string {
+	return promLabelEscaper.Replace(v)
+}
+
+func formatPromFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func promBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func promNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case bool:
+		return promBool(n), true
+	case time.Duration:
+		return n.Seconds(), true
+	default:
+		return 0, false
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,93 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsSnapshotCountsRecordsAndDrops(t *testing.T) {
+	resetForTest()
+	ResetMetrics()
+	defer ResetMetrics()
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false)
+	logger.Info("one")
+	logger.Warn("two")
+	logger.Warn("three")
+
+	ConfigureRecordLimiter(1, 1)
+	logger.Info("allowed")
+	logger.Info("dropped")
+	ConfigureRecordLimiter(0, 0)
+
+	snap := GetMetricsSnapshot()
+	text := snap.Records[metricsOutputText]
+	if text["info"] < 1 || text["warn"] != 2 {
+		t.Fatalf("unexpected text record counts: %v", text)
+	}
+	if snap.LimiterDropped == 0 {
+		t.Fatal("expected limiter drops to be counted")
+	}
+	h := snap.WriteLatency[metricsOutputText]
+	if h.Count != text["info"]+text["warn"] || len(h.Counts) != len(h.Buckets) {
+		t.Fatalf("unexpected histogram: %+v", h)
+	}
+	if len(snap.Pools) == 0 {
+		t.Fatal("expected tiered pool stats")
+	}
+}
+
+func TestMetricsHandlerServesPrometheusText(t *testing.T) {
+	resetForTest()
+	ResetMetrics()
+	defer ResetMetrics()
+
+	logger := NewLogger(&bytes.Buffer{}, true, false)
+	logger.Error("boom")
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE slog_records_total counter",
+		`slog_records_total{output="text",level="error"} 1`,
+		`slog_write_duration_seconds_bucket{output="text",le="+Inf"} 1`,
+		`slog_records_dropped_total{reason="limiter"} 0`,
+		"slog_subscription_dropped_total",
+		"slog_dlp_cache_requests_total",
+		"slog_pool_operations_total",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestPublishMetricsExpvar(t *testing.T) {
+	PublishMetricsExpvar("slog_test_metrics")
+	PublishMetricsExpvar("slog_test_metrics") // 重复发布不应 panic
+
+	v := expvar.Get("slog_test_metrics")
+	if v == nil {
+		t.Fatal("expected expvar to be published")
+	}
+	var snap MetricsSnapshot
+	if err := json.Unmarshal([]byte(v.String()), &snap); err != nil {
+		t.Fatalf("expvar value is not a metrics snapshot: %v", err)
+	}
+}
+
+func TestEscapePromLabel(t *testing.T) {
+	if got := escapePromLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Fatalf("unexpected escape result %q", got)
+	}
+}
@@ -100,6 +100,11 @@ func GetAsyncExecutorOptions() AsyncExecutorOptions {
 	return asyncExecutor.options
 }
 
+// GetAsyncDropCount returns how many async tasks were dropped because the queue was full.
+func GetAsyncDropCount() uint64 {
+	return asyncDropCount.Load()
+}
+
 func runAsyncTask(component string, task func() error) {
 	defer func() {
 		if rec := recover(); rec != nil {
@@ -0,0 +1,176 @@
+package modules
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// LevelDescriptor 描述一个日志级别在各输出中的呈现方式。
+type LevelDescriptor struct {
+	Level    slog.Level // 级别数值
+	Name     string     // JSON / logfmt / GELF 等结构化输出使用的名称，如 "Notice"
+	Short    string     // 控制台短名，如 "N"
+	Color    string     // 控制台 ANSI 颜色序列，为空时按数值区间取色
+	Severity int        // syslog 严重度 0-7
+}
+
+var (
+	errLevelName     = errors.New("level name must not be empty")
+	errLevelSeverity = errors.New("syslog severity must be within 0-7")
+	errLevelBuiltin  = errors.New("builtin level cannot be redefined")
+	errLevelConflict = errors.New("level name already registered for another value")
+)
+
+const (
+	levelTrace slog.Level = -8
+	levelFatal slog.Level = 12
+)
+
+var builtinLevels = []LevelDescriptor{
+	{Level: levelTrace, Name: "Trace", Short: "T", Color: "\033[95m", Severity: 7},
+	{Level: slog.LevelDebug, Name: "Debug", Short: "D", Color: "\033[94m", Severity: 7},
+	{Level: slog.LevelInfo, Name: "Info", Short: "I", Color: "\033[92m", Severity: 6},
+	{Level: slog.LevelWarn, Name: "Warn", Short: "W", Color: "\033[93m", Severity: 4},
+	{Level: slog.LevelError, Name: "Error", Short: "E", Color: "\033[91m", Severity: 3},
+	{Level: levelFatal, Name: "Fatal", Short: "F", Color: "\033[91m", Severity: 2},
+}
+
+var levelRegistry = struct {
+	mu     sync.RWMutex
+	byVal  map[slog.Level]LevelDescriptor
+	byName map[string]slog.Level
+}{
+	byVal:  make(map[slog.Level]LevelDescriptor),
+	byName: make(map[string]slog.Level),
+}
+
+func init() {
+	for _, d := range builtinLevels {
+		levelRegistry.byVal[d.Level] = d
+		levelRegistry.byName[strings.ToLower(d.Name)] = d.Level
+	}
+}
+
+// RegisterLevel 注册或更新自定义级别；内置级别不可覆盖。
+func RegisterLevel(d LevelDescriptor) error {
+	d.Name = strings.TrimSpace(d.Name)
+	if d.Name == "" {
+		return errLevelName
+	}
+	if d.Severity < 0 || d.Severity > 7 {
+		return errLevelSeverity
+	}
+	if d.Short == "" {
+		r, _ := utf8.DecodeRuneInString(d.Name)
+		d.Short = strings.ToUpper(string(r))
+	}
+	if isBuiltinLevel(d.Level) {
+		return errLevelBuiltin
+	}
+	name := strings.ToLower(d.Name)
+
+	levelRegistry.mu.Lock()
+	defer levelRegistry.mu.Unlock()
+	if existing, ok := levelRegistry.byName[name]; ok && existing != d.Level {
+		return errLevelConflict
+	}
+	if prev, ok := levelRegistry.byVal[d.Level]; ok {
+		delete(levelRegistry.byName, strings.ToLower(prev.Name))
+	}
+	levelRegistry.byVal[d.Level] = d
+	levelRegistry.byName[name] = d.Level
+	return nil
+}
+
+// UnregisterLevel 移除自定义级别，内置级别不受影响。
+func UnregisterLevel(level slog.Level) bool {
+	if isBuiltinLevel(level) {
+		return false
+	}
+	levelRegistry.mu.Lock()
+	defer levelRegistry.mu.Unlock()
+	d, ok := levelRegistry.byVal[level]
+	if !ok {
+		return false
+	}
+	delete(levelRegistry.byVal, level)
+	delete(levelRegistry.byName, strings.ToLower(d.Name))
+	return true
+}
+
+// LookupLevel 返回已注册级别的描述。
+func LookupLevel(level slog.Level) (LevelDescriptor, bool) {
+	levelRegistry.mu.RLock()
+	d, ok := levelRegistry.byVal[level]
+	levelRegistry.mu.RUnlock()
+	return d, ok
+}
+
+// LookupCustomLevel 仅返回通过 RegisterLevel 注册的自定义级别，内置级别返回 false。
+// 各输出据此保持内置级别原有的拼写，只对自定义级别使用注册名称。
+func LookupCustomLevel(level slog.Level) (LevelDescriptor, bool) {
+	if isBuiltinLevel(level) {
+		return LevelDescriptor{}, false
+	}
+	return LookupLevel(level)
+}
+
+// ParseLevel 按名称（不区分大小写）查找已注册级别。
+func ParseLevel(name string) (slog.Level, bool) {
+	levelRegistry.mu.RLock()
+	level, ok := levelRegistry.byName[strings.ToLower(strings.TrimSpace(name))]
+	levelRegistry.mu.RUnlock()
+	return level, ok
+}
+
+// Levels 返回全部已注册级别，按数值升序。
+func Levels() []LevelDescriptor {
+	levelRegistry.mu.RLock()
+	out := make([]LevelDescriptor, 0, len(levelRegistry.byVal))
+	for _, d := range levelRegistry.byVal {
+		out = append(out, d)
+	}
+	levelRegistry.mu.RUnlock()
+	slices.SortFunc(out, func(a, b LevelDescriptor) int { return int(a.Level) - int(b.Level) })
+	return out
+}
+
+// LevelName 返回结构化输出使用的级别名称，未注册时回退到 slog 默认格式（如 "INFO+2"）。
+func LevelName(level slog.Level) string {
+	if d, ok := LookupLevel(level); ok {
+		return d.Name
+	}
+	return level.String()
+}
+
+// LevelSeverity 返回级别对应的 syslog 严重度，未注册时按数值区间映射。
+func LevelSeverity(level slog.Level) int {
+	if d, ok := LookupLevel(level); ok {
+		return d.Severity
+	}
+	switch {
+	case level >= levelFatal:
+		return 2
+	case level >= slog.LevelError:
+		return 3
+	case level >= slog.LevelWarn:
+		return 4
+	case level >= slog.LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
+
+func isBuiltinLevel(level slog.Level) bool {
+	for _, d := range builtinLevels {
+		if d.Level == level {
+			return true
+		}
+	}
+	return false
+}
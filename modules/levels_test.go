@@ -0,0 +1,53 @@
+package modules
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestRegisterLevel(t *testing.T) {
+	notice := slog.Level(2)
+	defer UnregisterLevel(notice)
+
+	if err := RegisterLevel(LevelDescriptor{Level: slog.LevelInfo, Name: "Information", Severity: 6}); err == nil {
+		t.Fatal("expected builtin level to be protected")
+	}
+	if err := RegisterLevel(LevelDescriptor{Level: notice, Name: "Notice", Severity: 9}); err == nil {
+		t.Fatal("expected invalid severity to be rejected")
+	}
+	if err := RegisterLevel(LevelDescriptor{Level: notice, Name: "warn", Severity: 5}); err == nil {
+		t.Fatal("expected name conflict with builtin to be rejected")
+	}
+	if err := RegisterLevel(LevelDescriptor{Level: notice, Name: "Notice", Severity: 5}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	d, ok := LookupLevel(notice)
+	if !ok || d.Short != "N" || LevelName(notice) != "Notice" || LevelSeverity(notice) != 5 {
+		t.Fatalf("unexpected descriptor: %+v", d)
+	}
+	if level, ok := ParseLevel("NOTICE"); !ok || level != notice {
+		t.Fatalf("ParseLevel = %v, %v", level, ok)
+	}
+
+	if !UnregisterLevel(notice) {
+		t.Fatal("expected custom level to be removed")
+	}
+	if LevelName(notice) != "INFO+2" || LevelSeverity(notice) != 6 {
+		t.Fatalf("unexpected fallback: %s %d", LevelName(notice), LevelSeverity(notice))
+	}
+	if LevelSeverity(slog.Level(12)) != 2 || LevelName(slog.Level(-8)) != "Trace" {
+		t.Fatal("unexpected builtin mapping")
+	}
+}
+
+func TestRegisterLevelMultibyteShortName(t *testing.T) {
+	audit := slog.Level(6)
+	defer UnregisterLevel(audit)
+	if err := RegisterLevel(LevelDescriptor{Level: audit, Name: "审计", Severity: 5}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if d, _ := LookupLevel(audit); d.Short != "审" {
+		t.Fatalf("short name must keep the whole first rune, got %q", d.Short)
+	}
+}
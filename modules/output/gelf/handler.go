@@ -3,10 +3,14 @@ package gelf
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/darkit/slog/modules"
 )
@@ -24,12 +28,20 @@ type Options struct {
 // Handler 兼容 GELF 1.1。
 type Handler struct {
 	w           modules.WriteSyncer
-	mu          sync.Mutex
+	mu          *sync.Mutex
 	level       slog.Leveler
 	replaceAttr func(groups []string, a slog.Attr) slog.Attr
 	addSource   bool
 	host        string
 	facility    string
+	groups      groupState
+	attrs       []field // WithAttrs 预先展开的附加字段
+}
+
+// field 是展开后的 GELF 附加字段，key 已带 "_" 与分组前缀。
+type field struct {
+	key string
+	val any
 }
 
 // New 创建 handler。
@@ -48,6 +60,7 @@ func New(opt Options) *Handler {
 	}
 	return &Handler{
 		w:           opt.Writer,
+		mu:          &sync.Mutex{},
 		level:       opt.Level,
 		replaceAttr: opt.ReplaceAttr,
 		addSource:   opt.AddSource,
@@ -67,7 +80,9 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 		payload["host"] = h.host
 	}
 	payload["short_message"] = r.Message
-	payload["timestamp"] = float64(r.Time.UnixNano()) / 1e9
+	if !r.Time.IsZero() {
+		payload["timestamp"] = float64(r.Time.UnixNano()) / 1e9
+	}
 	payload["level"] = h.levelToSyslog(r.Level)
 	if h.facility != "" {
 		payload["facility"] = h.facility
@@ -77,9 +92,12 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 		payload["_source"] = modules.SourceLabel(modules.Frame(r.PC))
 	}
 
-	groups := groupState{}
+	for _, f := range h.attrs {
+		payload[f.key] = f.val
+	}
+	groups := h.groups.clone()
 	r.Attrs(func(a slog.Attr) bool {
-		h.appendAttr(payload, &groups, a)
+		h.appendAttr(func(key string, val any) { payload[key] = val }, &groups, a)
 		return true
 	})
 
@@ -88,67 +106,237 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 		return err
 	}
 
+	// 一条消息一次 Write，传输层据此划分消息边界。
+	data = append(data, '\n')
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	if _, err = h.w.Write(data); err != nil {
-		return err
+	_, err = h.w.Write(data)
+	return err
+}
+
+// WithAttrs 在当前分组下预先展开 attrs，后续记录原样附加。
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
 	}
-	_, _ = h.w.Write([]byte{'\n'})
-	return nil
+	h2 := h.clone()
+	groups := h2.groups.clone()
+	for _, a := range attrs {
+		h2.appendAttr(func(key string, val any) {
+			h2.attrs = append(h2.attrs, field{key: key, val: val})
+		}, &groups, a)
+	}
+	return h2
 }
 
-func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler { _ = attrs; return h }
-func (h *Handler) WithGroup(name string) slog.Handler       { _ = name; return h }
+// WithGroup 打开分组，之后的字段名为 "_group.key"。
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.groups.Push(name)
+	return h2
+}
 
-func (h *Handler) appendAttr(payload map[string]any, groups *groupState, attr slog.Attr) {
+// clone 复制 handler 配置，共享写入器，写入仍由同一把锁串行化。
+func (h *Handler) clone() *Handler {
+	return &Handler{
+		w:           h.w,
+		mu:          h.mu,
+		level:       h.level,
+		replaceAttr: h.replaceAttr,
+		addSource:   h.addSource,
+		host:        h.host,
+		facility:    h.facility,
+		groups:      h.groups.clone(),
+		attrs:       slices.Clip(h.attrs),
+	}
+}
+
+func (h *Handler) appendAttr(emit func(key string, val any), groups *groupState, attr slog.Attr) {
 	attr.Value = attr.Value.Resolve()
-	if h.replaceAttr != nil {
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if h.replaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
 		attr = h.replaceAttr(groups.Values(), attr)
-		if attr.Key == "" {
+		attr.Value = attr.Value.Resolve()
+		if attr.Equal(slog.Attr{}) {
 			return
 		}
 	}
 
 	switch attr.Value.Kind() {
 	case slog.KindGroup:
+		// 空分组不输出；空键分组内联到当前层级。
 		groups.Push(attr.Key)
 		for _, a := range attr.Value.Group() {
-			h.appendAttr(payload, groups, a)
+			h.appendAttr(emit, groups, a)
+		}
+		if attr.Key != "" {
+			groups.Pop()
 		}
-		groups.Pop()
 	default:
-		key := "_" + strings.Join(append(groups.Values(), attr.Key), ".")
-		payload[key] = attr.Value.Any()
+		// 非分组属性的键为空（如 ReplaceAttr 只清空了键）时丢弃，避免输出无键字段。
+		if attr.Key == "" {
+			return
+		}
+		key := "_" + attr.Key
+		if prefix := groups.Prefix(); prefix != "" {
+			key = "_" + prefix + "." + attr.Key
+		}
+		emit(key, attr.Value.Any())
 	}
 }
 
 func (h *Handler) levelToSyslog(level slog.Level) int {
-	switch level {
-	case slog.LevelError:
-		return 3
-	case slog.LevelWarn:
-		return 4
-	case slog.LevelInfo:
-		return 6
-	default:
-		return 7
-	}
+	return modules.LevelSeverity(level)
 }
 
 // 模块注册：gelf
 func init() {
 	if err := modules.RegisterFactory("gelf", func(config modules.Config) (modules.Module, error) {
-		h := New(Options{})
-		return modules.NewHandlerModule("gelf", h), nil
+		m := NewModule()
+		if err := m.Configure(config); err != nil {
+			return nil, err
+		}
+		return m, nil
 	}); err != nil {
 		modules.ReportAsyncError("registry.gelf", err)
 	}
 }
 
+// Module 是 gelf 的模块适配器，Configure 可重复调用以重建传输与 handler。
+type Module struct {
+	*modules.BaseModule
+	mu     sync.Mutex
+	writer modules.WriteSyncer
+}
+
+// NewModule 创建未配置的 gelf 模块。
+func NewModule() *Module {
+	return &Module{BaseModule: modules.NewBaseModule("gelf", modules.TypeHandler, 100)}
+}
+
+// Configure 校验配置并重建 handler；新传输就绪后才替换并关闭旧的传输，失败时保留原状态。
+func (m *Module) Configure(config modules.Config) error {
+	opt, err := optionsFromConfig(config)
+	if err != nil {
+		return err
+	}
+	if err := m.BaseModule.Configure(config); err != nil {
+		closeWriter(opt.Writer)
+		return err
+	}
+
+	m.mu.Lock()
+	prev := m.writer
+	m.writer = opt.Writer
+	m.SetHandler(New(opt))
+	m.mu.Unlock()
+
+	closeWriter(prev)
+	return nil
+}
+
+// closeWriter 关闭模块自建的网络传输，stdout 等无 Close 的 writer 忽略。
+func closeWriter(w modules.WriteSyncer) {
+	if c, ok := w.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
+// optionsFromConfig 把模块配置转换为 Options；先校验全部字段，transport 为 udp / tcp 时再创建对应的网络传输。
+func optionsFromConfig(config modules.Config) (Options, error) {
+	var cfg struct {
+		Transport    string `json:"transport"` // stdout（默认）/ udp / tcp
+		Addr         string `json:"addr"`
+		Level        string `json:"level"`
+		Host         string `json:"host"`
+		Facility     string `json:"facility"`
+		AddSource    bool   `json:"add_source"`
+		Compression  string `json:"compression"`
+		ChunkSize    int    `json:"chunk_size"`
+		DialTimeout  string `json:"dial_timeout"`
+		WriteTimeout string `json:"write_timeout"`
+	}
+	if err := config.Bind(&cfg); err != nil {
+		return Options{}, err
+	}
+
+	opt := Options{
+		Host:      cfg.Host,
+		Facility:  cfg.Facility,
+		AddSource: cfg.AddSource,
+	}
+	if cfg.Level != "" {
+		level, ok := modules.ParseLevel(cfg.Level)
+		if !ok {
+			return Options{}, errInvalidLevel
+		}
+		opt.Level = level
+	}
+	dialTimeout, err := parseTimeout("dial_timeout", cfg.DialTimeout)
+	if err != nil {
+		return Options{}, err
+	}
+	writeTimeout, err := parseTimeout("write_timeout", cfg.WriteTimeout)
+	if err != nil {
+		return Options{}, err
+	}
+
+	switch strings.ToLower(cfg.Transport) {
+	case "", "stdout":
+	case "udp":
+		w, err := NewUDPWriter(UDPOption{
+			Addr:         cfg.Addr,
+			Compression:  Compression(cfg.Compression),
+			ChunkSize:    cfg.ChunkSize,
+			DialTimeout:  dialTimeout,
+			WriteTimeout: writeTimeout,
+		})
+		if err != nil {
+			return Options{}, err
+		}
+		opt.Writer = w
+	case "tcp":
+		opt.Writer = NewTCPWriter(TCPOption{
+			Addr:         cfg.Addr,
+			DialTimeout:  dialTimeout,
+			WriteTimeout: writeTimeout,
+		})
+	default:
+		return Options{}, errInvalidTransport
+	}
+	return opt, nil
+}
+
+// parseTimeout 解析超时配置，空串表示使用传输默认值。
+func parseTimeout(name, s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("gelf: invalid %s %q", name, s)
+	}
+	return d, nil
+}
+
 // --- helpers ---
 
+// groupState 记录当前分组路径，joined 缓存每一层的 "a.b" 前缀。
 type groupState struct {
-	names []string
+	names  []string
+	joined []string
+}
+
+func (g *groupState) clone() groupState {
+	return groupState{
+		names:  slices.Clone(g.names),
+		joined: slices.Clone(g.joined),
+	}
 }
 
 func (g *groupState) Push(name string) {
@@ -156,6 +344,11 @@ func (g *groupState) Push(name string) {
 		return
 	}
 	g.names = append(g.names, name)
+	if len(g.joined) == 0 {
+		g.joined = append(g.joined, name)
+		return
+	}
+	g.joined = append(g.joined, g.joined[len(g.joined)-1]+"."+name)
 }
 
 func (g *groupState) Pop() {
@@ -163,6 +356,14 @@ func (g *groupState) Pop() {
 		return
 	}
 	g.names = g.names[:len(g.names)-1]
+	g.joined = g.joined[:len(g.joined)-1]
 }
 
 func (g *groupState) Values() []string { return g.names }
+
+func (g *groupState) Prefix() string {
+	if len(g.joined) == 0 {
+		return ""
+	}
+	return g.joined[len(g.joined)-1]
+}
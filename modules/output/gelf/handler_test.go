@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"strings"
 	"testing"
+	"testing/slogtest"
 	"time"
 )
 
@@ -35,3 +37,100 @@ func TestGELFHandler(t *testing.T) {
 		t.Fatalf("user mismatch: %v", payload["_user"])
 	}
 }
+
+func TestGELFHandlerLevelSeverity(t *testing.T) {
+	h := New(Options{Writer: &bytes.Buffer{}})
+	cases := map[slog.Level]int{
+		slog.Level(-8):  7,
+		slog.LevelInfo:  6,
+		slog.LevelWarn:  4,
+		slog.LevelError: 3,
+		slog.Level(12):  2,
+		slog.Level(10):  3,
+	}
+	for level, want := range cases {
+		if got := h.levelToSyslog(level); got != want {
+			t.Errorf("levelToSyslog(%v) = %d, want %d", level, got, want)
+		}
+	}
+}
+
+func TestGELFHandlerSlogtest(t *testing.T) {
+	var buf bytes.Buffer
+	newHandler := func(*testing.T) slog.Handler {
+		buf.Reset()
+		return New(Options{Writer: &buf, Host: "test-host"})
+	}
+	result := func(t *testing.T) map[string]any {
+		var payload map[string]any
+		if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &payload); err != nil {
+			t.Fatalf("unmarshal error: %v", err)
+		}
+		// 还原为 slogtest 期望的结构：内置字段改名，附加字段去掉 "_" 并按 "." 嵌套。
+		out := map[string]any{}
+		for key, val := range payload {
+			switch key {
+			case "short_message":
+				out[slog.MessageKey] = val
+			case "timestamp":
+				out[slog.TimeKey] = val
+			case "level":
+				out[slog.LevelKey] = val
+			default:
+				if !strings.HasPrefix(key, "_") {
+					continue
+				}
+				m := out
+				parts := strings.Split(key[1:], ".")
+				for _, p := range parts[:len(parts)-1] {
+					sub, ok := m[p].(map[string]any)
+					if !ok {
+						sub = map[string]any{}
+						m[p] = sub
+					}
+					m = sub
+				}
+				m[parts[len(parts)-1]] = val
+			}
+		}
+		return out
+	}
+	slogtest.Run(t, newHandler, result)
+}
+
+func TestGELFHandlerWithAttrsAndGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var h slog.Handler = New(Options{Writer: buf})
+	h = h.WithAttrs([]slog.Attr{slog.String("request_id", "r1")}).WithGroup("http").WithAttrs([]slog.Attr{slog.String("method", "GET")})
+	slog.New(h).Info("done", "status", 200)
+
+	var payload map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &payload); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if payload["_request_id"] != "r1" || payload["_http.method"] != "GET" || payload["_http.status"] != float64(200) {
+		t.Fatalf("unexpected fields: %v", payload)
+	}
+}
+
+func TestGELFHandlerReplaceAttrClearsKey(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := New(Options{Writer: buf, ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == "secret" {
+			a.Key = "" // 只清空键，值保持不变
+		}
+		return a
+	}})
+	slog.New(h).Info("m", "secret", "s3cr3t", "user", "alice", slog.Group("", slog.String("inline", "yes")))
+
+	var payload map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &payload); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if _, ok := payload["_"]; ok || strings.Contains(buf.String(), "s3cr3t") {
+		t.Fatalf("attr with cleared key must be dropped: %s", buf.String())
+	}
+	if payload["_user"] != "alice" || payload["_inline"] != "yes" {
+		t.Fatalf("other attrs must be kept: %s", buf.String())
+	}
+}
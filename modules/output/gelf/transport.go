@@ -0,0 +1,204 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	outputnet "github.com/darkit/slog/modules/output/net"
+)
+
+// Compression 是 UDP 传输使用的压缩方式。
+type Compression string
+
+const (
+	CompressionNone Compression = "none"
+	CompressionGzip Compression = "gzip"
+	CompressionZlib Compression = "zlib"
+)
+
+const (
+	// DefaultChunkSize 是 UDP 分块的默认大小（含 12 字节块头），适合公网 MTU。
+	DefaultChunkSize = 1420
+	// MaxChunks 是 GELF 规范允许的最大分块数，超过时消息被丢弃。
+	MaxChunks = 128
+
+	chunkHeaderSize = 12
+	minChunkSize    = chunkHeaderSize + 1
+)
+
+// chunkMagic 是 GELF 分块的魔数 0x1e 0x0f。
+var chunkMagic = [2]byte{0x1e, 0x0f}
+
+var (
+	errInvalidCompression = errors.New("gelf: invalid compression")
+	errInvalidTransport   = errors.New("gelf: invalid transport")
+	errInvalidLevel       = errors.New("gelf: invalid level")
+	errTooManyChunks      = errors.New("gelf: message exceeds 128 chunks")
+)
+
+// ParseCompression 解析压缩方式名称，空字符串视为 gzip（Graylog 默认）。
+func ParseCompression(name string) (Compression, error) {
+	switch c := Compression(strings.ToLower(strings.TrimSpace(name))); c {
+	case "":
+		return CompressionGzip, nil
+	case CompressionNone, CompressionGzip, CompressionZlib:
+		return c, nil
+	default:
+		return "", errInvalidCompression
+	}
+}
+
+// UDPOption 配置 GELF UDP 传输。
+type UDPOption struct {
+	Addr         string
+	Compression  Compression // 为空时使用 gzip
+	ChunkSize    int         // 单个数据报的最大字节数，<=12 时使用 DefaultChunkSize
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+	Dial         outputnet.DialFunc
+}
+
+// UDPWriter 把每次 Write 的 GELF 消息压缩后发送，超过 ChunkSize 时按规范分块。
+type UDPWriter struct {
+	sender      *outputnet.Sender
+	compression Compression
+	chunkSize   int
+}
+
+// NewUDPWriter 创建 UDP 传输，可作为 Options.Writer 使用。
+func NewUDPWriter(opt UDPOption) (*UDPWriter, error) {
+	compression, err := ParseCompression(string(opt.Compression))
+	if err != nil {
+		return nil, err
+	}
+	if opt.ChunkSize < minChunkSize {
+		opt.ChunkSize = DefaultChunkSize
+	}
+	return &UDPWriter{
+		sender: outputnet.NewSender(outputnet.SenderOption{
+			Network:      "udp",
+			Addr:         opt.Addr,
+			DialTimeout:  opt.DialTimeout,
+			WriteTimeout: opt.WriteTimeout,
+			Dial:         opt.Dial,
+		}),
+		compression: compression,
+		chunkSize:   opt.ChunkSize,
+	}, nil
+}
+
+// Write 发送一条 GELF 消息，末尾换行会被去掉。
+func (w *UDPWriter) Write(p []byte) (int, error) {
+	payload, err := compress(bytes.TrimSuffix(p, []byte{'\n'}), w.compression)
+	if err != nil {
+		return 0, err
+	}
+	chunks, err := chunkMessage(payload, w.chunkSize, rand.Uint64())
+	if err != nil {
+		return 0, err
+	}
+	for _, chunk := range chunks {
+		if _, err := w.sender.Write(chunk); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close 关闭底层连接。
+func (w *UDPWriter) Close() error {
+	return w.sender.Close()
+}
+
+func compress(p []byte, c Compression) ([]byte, error) {
+	var buf bytes.Buffer
+	switch c {
+	case CompressionGzip:
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(p); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	case CompressionZlib:
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(p); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return p, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// chunkMessage 按 GELF 规范切分消息：每块以魔数、8 字节消息 ID、序号与总块数开头。
+// 不超过 size 的消息原样返回。
+func chunkMessage(p []byte, size int, id uint64) ([][]byte, error) {
+	if len(p) <= size {
+		return [][]byte{p}, nil
+	}
+	data := size - chunkHeaderSize
+	count := (len(p) + data - 1) / data
+	if count > MaxChunks {
+		return nil, errTooManyChunks
+	}
+	chunks := make([][]byte, 0, count)
+	for seq := 0; seq < count; seq++ {
+		part := p[seq*data : min((seq+1)*data, len(p))]
+		chunk := make([]byte, chunkHeaderSize, chunkHeaderSize+len(part))
+		chunk[0], chunk[1] = chunkMagic[0], chunkMagic[1]
+		binary.BigEndian.PutUint64(chunk[2:10], id)
+		chunk[10] = byte(seq)
+		chunk[11] = byte(count)
+		chunks = append(chunks, append(chunk, part...))
+	}
+	return chunks, nil
+}
+
+// TCPOption 配置 GELF TCP 传输。
+type TCPOption struct {
+	Addr         string
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+	Dial         outputnet.DialFunc
+}
+
+// TCPWriter 通过 outputnet.Sender 发送 GELF 消息，每条消息以空字节结尾。
+type TCPWriter struct {
+	sender *outputnet.Sender
+}
+
+// NewTCPWriter 创建 TCP 传输，可作为 Options.Writer 使用。
+func NewTCPWriter(opt TCPOption) *TCPWriter {
+	return &TCPWriter{sender: outputnet.NewSender(outputnet.SenderOption{
+		Network:      "tcp",
+		Addr:         opt.Addr,
+		DialTimeout:  opt.DialTimeout,
+		WriteTimeout: opt.WriteTimeout,
+		Dial:         opt.Dial,
+		Delimiter:    []byte{0},
+	})}
+}
+
+// Write 发送一条 GELF 消息，末尾换行替换为空字节分隔符。
+func (w *TCPWriter) Write(p []byte) (int, error) {
+	if _, err := w.sender.Write(bytes.TrimSuffix(p, []byte{'\n'})); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close 关闭底层连接。
+func (w *TCPWriter) Close() error {
+	return w.sender.Close()
+}
@@ -0,0 +1,302 @@
+package gelf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darkit/slog/modules"
+)
+
+func TestChunkMessage(t *testing.T) {
+	msg := bytes.Repeat([]byte("abcdefghij"), 10) // 100 字节
+	chunks, err := chunkMessage(msg, 32, 0x0102030405060708)
+	if err != nil {
+		t.Fatalf("chunk error: %v", err)
+	}
+	if len(chunks) != 5 { // 每块 20 字节数据
+		t.Fatalf("expected 5 chunks, got %d", len(chunks))
+	}
+	var joined []byte
+	for i, c := range chunks {
+		if len(c) > 32 {
+			t.Fatalf("chunk %d exceeds size: %d", i, len(c))
+		}
+		if c[0] != 0x1e || c[1] != 0x0f {
+			t.Fatalf("chunk %d missing magic bytes: %x", i, c[:2])
+		}
+		if id := binary.BigEndian.Uint64(c[2:10]); id != 0x0102030405060708 {
+			t.Fatalf("chunk %d message id mismatch: %x", i, id)
+		}
+		if int(c[10]) != i || c[11] != 5 {
+			t.Fatalf("chunk %d sequence mismatch: %d/%d", i, c[10], c[11])
+		}
+		joined = append(joined, c[chunkHeaderSize:]...)
+	}
+	if !bytes.Equal(joined, msg) {
+		t.Fatalf("reassembled message mismatch")
+	}
+
+	small, _ := chunkMessage([]byte("short"), 32, 1)
+	if len(small) != 1 || string(small[0]) != "short" {
+		t.Fatalf("small message should not be chunked: %q", small)
+	}
+
+	if _, err := chunkMessage(make([]byte, MaxChunks*20+1), 32, 1); err != errTooManyChunks {
+		t.Fatalf("expected errTooManyChunks, got %v", err)
+	}
+}
+
+func TestUDPWriterCompression(t *testing.T) {
+	for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionZlib} {
+		t.Run(string(c), func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			defer conn.Close()
+
+			w, err := NewUDPWriter(UDPOption{Addr: conn.LocalAddr().String(), Compression: c})
+			if err != nil {
+				t.Fatalf("new writer: %v", err)
+			}
+			defer w.Close()
+
+			h := New(Options{Writer: w, Host: "test-host"})
+			if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "over udp", 0)); err != nil {
+				t.Fatalf("handle: %v", err)
+			}
+
+			buf := make([]byte, 65536)
+			_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			payload := decompress(t, buf[:n], c)
+			var m map[string]any
+			if err := json.Unmarshal(payload, &m); err != nil {
+				t.Fatalf("unmarshal %q: %v", payload, err)
+			}
+			if m["short_message"] != "over udp" {
+				t.Fatalf("unexpected payload: %v", m)
+			}
+		})
+	}
+}
+
+func TestUDPWriterChunksLargeMessages(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	w, err := NewUDPWriter(UDPOption{Addr: conn.LocalAddr().String(), Compression: CompressionNone, ChunkSize: 64})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+	defer w.Close()
+
+	msg := []byte(`{"short_message":"` + strings.Repeat("x", 200) + `"}` + "\n")
+	if _, err := w.Write(msg); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var joined []byte
+	buf := make([]byte, 1024)
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if n > 64 || buf[0] != 0x1e || buf[1] != 0x0f {
+			t.Fatalf("unexpected datagram: %d bytes %x", n, buf[:2])
+		}
+		joined = append(joined, buf[chunkHeaderSize:n]...)
+		if buf[10] == buf[11]-1 {
+			break
+		}
+	}
+	if !bytes.Equal(joined, bytes.TrimSuffix(msg, []byte{'\n'})) {
+		t.Fatalf("reassembled mismatch: %q", joined)
+	}
+}
+
+func TestTCPWriterNullDelimited(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var frames []string
+		for len(frames) < 2 {
+			frame, err := r.ReadString(0)
+			if err != nil {
+				break
+			}
+			frames = append(frames, frame)
+		}
+		received <- frames
+	}()
+
+	w := NewTCPWriter(TCPOption{Addr: ln.Addr().String()})
+	defer w.Close()
+	logger := slog.New(New(Options{Writer: w}))
+	logger.Info("first")
+	logger.Info("second")
+
+	select {
+	case frames := <-received:
+		if len(frames) != 2 {
+			t.Fatalf("expected 2 frames, got %q", frames)
+		}
+		for _, f := range frames {
+			if !strings.HasSuffix(f, "}\x00") {
+				t.Fatalf("frame should end with a null byte and no newline: %q", f)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for frames")
+	}
+}
+
+func TestGELFFactoryConfig(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	m, err := modules.CreateModule("gelf", modules.Config{
+		"transport":   "udp",
+		"addr":        conn.LocalAddr().String(),
+		"compression": "none",
+		"level":       "warn",
+		"host":        "cfg-host",
+		"facility":    "billing",
+	})
+	if err != nil {
+		t.Fatalf("create module: %v", err)
+	}
+	logger := slog.New(m.Handler())
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	buf := make([]byte, 65536)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(buf[:n], &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload["short_message"] != "kept" || payload["host"] != "cfg-host" || payload["facility"] != "billing" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+
+	if _, err := modules.CreateModule("gelf", modules.Config{"transport": "carrier-pigeon"}); err == nil {
+		t.Fatal("expected error for unknown transport")
+	}
+	if _, err := modules.CreateModule("gelf", modules.Config{"transport": "udp", "compression": "lz4"}); err == nil {
+		t.Fatal("expected error for unknown compression")
+	}
+}
+
+func TestGELFModuleReconfigure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(io.Discard, conn)
+		close(closed)
+	}()
+
+	m, err := modules.CreateModule("gelf", modules.Config{"transport": "tcp", "addr": ln.Addr().String()})
+	if err != nil {
+		t.Fatalf("create module: %v", err)
+	}
+	slog.New(m.Handler()).Info("connect")
+
+	for _, bad := range []modules.Config{
+		{"level": "verbose"},
+		{"dial_timeout": "soon"},
+		{"write_timeout": "-1s"},
+		{"transport": "carrier-pigeon"},
+	} {
+		if err := m.Configure(bad); err == nil {
+			t.Fatalf("expected error for %v", bad)
+		}
+	}
+	select {
+	case <-closed:
+		t.Fatal("invalid config must keep the previous transport")
+	default:
+	}
+
+	if err := m.Configure(modules.Config{"level": "error"}); err != nil {
+		t.Fatalf("reconfigure: %v", err)
+	}
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("previous tcp transport must be closed after reconfigure")
+	}
+	h, ok := m.Handler().(*Handler)
+	if !ok || h.Enabled(context.Background(), slog.LevelWarn) || !h.Enabled(context.Background(), slog.LevelError) {
+		t.Fatalf("handler must be rebuilt with the new level: %#v", m.Handler())
+	}
+}
+
+func decompress(t *testing.T, p []byte, c Compression) []byte {
+	t.Helper()
+	var r io.Reader
+	var err error
+	switch c {
+	case CompressionGzip:
+		r, err = gzip.NewReader(bytes.NewReader(p))
+	case CompressionZlib:
+		r, err = zlib.NewReader(bytes.NewReader(p))
+	default:
+		return p
+	}
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	return out
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,11 +16,13 @@ import (
 // Handler 以 logfmt 形式输出，便于 Loki/Vector 等收集器解析。
 type Handler struct {
 	w           modules.WriteSyncer
-	mu          sync.Mutex
+	mu          *sync.Mutex
 	level       slog.Leveler
 	replaceAttr func(groups []string, a slog.Attr) slog.Attr
 	addSource   bool
 	timeFormat  string
+	groups      groupState // WithGroup 打开的分组
+	attrs       string     // WithAttrs 预先编码的属性
 }
 
 // Option 用于创建 Handler。
@@ -44,6 +47,7 @@ func New(opt Option) *Handler {
 	}
 	return &Handler{
 		w:           opt.Writer,
+		mu:          &sync.Mutex{},
 		level:       opt.Level,
 		replaceAttr: opt.ReplaceAttr,
 		addSource:   opt.AddSource,
@@ -59,7 +63,7 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 	var buf bytes.Buffer
 
 	rep := h.replaceAttr
-	groups := groupState{}
+	groups := h.groups.clone()
 
 	if !r.Time.IsZero() {
 		ts := r.Time.Round(0)
@@ -92,6 +96,7 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 
 	buf.WriteString("msg=")
 	writeStringValue(&buf, r.Message)
+	buf.WriteString(h.attrs)
 
 	r.Attrs(func(a slog.Attr) bool {
 		h.appendAttr(&buf, &groups, a)
@@ -106,14 +111,43 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 	return err
 }
 
+// WithAttrs 在当前分组下预先编码 attrs，后续记录原样附加。
 func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	_ = attrs
-	return h
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := h.clone()
+	var buf bytes.Buffer
+	groups := h2.groups.clone()
+	for _, a := range attrs {
+		h2.appendAttr(&buf, &groups, a)
+	}
+	h2.attrs += buf.String()
+	return h2
 }
 
+// WithGroup 打开分组，之后的属性键以 "group." 为前缀。
 func (h *Handler) WithGroup(name string) slog.Handler {
-	_ = name
-	return h
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.groups.Push(name)
+	return h2
+}
+
+// clone 复制 handler 配置，共享写入器，写入仍由同一把锁串行化。
+func (h *Handler) clone() *Handler {
+	return &Handler{
+		w:           h.w,
+		mu:          h.mu,
+		level:       h.level,
+		replaceAttr: h.replaceAttr,
+		addSource:   h.addSource,
+		timeFormat:  h.timeFormat,
+		groups:      h.groups.clone(),
+		attrs:       h.attrs,
+	}
 }
 
 // 模块注册
@@ -128,8 +162,17 @@ func init() {
 
 // --- helpers ---
 
+// groupState 记录当前分组路径，joined 缓存每一层的 "a.b" 前缀。
 type groupState struct {
-	names []string
+	names  []string
+	joined []string
+}
+
+func (g *groupState) clone() groupState {
+	return groupState{
+		names:  slices.Clone(g.names),
+		joined: slices.Clone(g.joined),
+	}
 }
 
 func (g *groupState) Push(name string) {
@@ -137,6 +180,11 @@ func (g *groupState) Push(name string) {
 		return
 	}
 	g.names = append(g.names, name)
+	if len(g.joined) == 0 {
+		g.joined = append(g.joined, name)
+		return
+	}
+	g.joined = append(g.joined, g.joined[len(g.joined)-1]+"."+name)
 }
 
 func (g *groupState) Pop() {
@@ -144,30 +192,52 @@ func (g *groupState) Pop() {
 		return
 	}
 	g.names = g.names[:len(g.names)-1]
+	g.joined = g.joined[:len(g.joined)-1]
 }
 
 func (g *groupState) Values() []string {
 	return g.names
 }
 
+func (g *groupState) Prefix() string {
+	if len(g.joined) == 0 {
+		return ""
+	}
+	return g.joined[len(g.joined)-1]
+}
+
 func (h *Handler) appendAttr(buf *bytes.Buffer, groups *groupState, attr slog.Attr) {
 	attr.Value = attr.Value.Resolve()
-	if h.replaceAttr != nil {
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if h.replaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
 		attr = h.replaceAttr(groups.Values(), attr)
-		if attr.Key == "" {
+		attr.Value = attr.Value.Resolve()
+		if attr.Equal(slog.Attr{}) {
 			return
 		}
 	}
 
 	switch attr.Value.Kind() {
 	case slog.KindGroup:
+		// 空分组不输出；空键分组内联到当前层级。
 		groups.Push(attr.Key)
 		for _, a := range attr.Value.Group() {
 			h.appendAttr(buf, groups, a)
 		}
-		groups.Pop()
+		if attr.Key != "" {
+			groups.Pop()
+		}
 	default:
-		key := strings.Join(append(groups.Values(), attr.Key), ".")
+		// 非分组属性的键为空（如 ReplaceAttr 只清空了键）时丢弃，避免输出无键字段。
+		if attr.Key == "" {
+			return
+		}
+		key := attr.Key
+		if prefix := groups.Prefix(); prefix != "" {
+			key = prefix + "." + key
+		}
 		buf.WriteByte(' ')
 		buf.WriteString(key)
 		buf.WriteByte('=')
@@ -222,17 +292,7 @@ func sourceLabel(pc uintptr) string {
 	return modules.SourceLabel(f)
 }
 
+// levelString 返回内置或已注册级别的名称，未注册级别与核心输出一致（如 "INFO+2"）。
 func levelString(l slog.Level) string {
-	switch l {
-	case slog.LevelDebug:
-		return "Debug"
-	case slog.LevelWarn:
-		return "Warn"
-	case slog.LevelError:
-		return "Error"
-	case slog.LevelInfo:
-		fallthrough
-	default:
-		return "Info"
-	}
+	return modules.LevelName(l)
 }
@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"log/slog"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/slogtest"
 	"time"
+
+	"github.com/darkit/slog/modules"
 )
 
 func TestLogfmtHandler(t *testing.T) {
@@ -28,3 +32,127 @@ func TestLogfmtHandler(t *testing.T) {
 		t.Fatalf("missing attrs: %s", out)
 	}
 }
+
+func TestLogfmtHandlerSlogtest(t *testing.T) {
+	var buf bytes.Buffer
+	newHandler := func(*testing.T) slog.Handler {
+		buf.Reset()
+		return New(Option{Writer: &buf, TimeFormat: time.RFC3339Nano})
+	}
+	result := func(t *testing.T) map[string]any {
+		return parseLogfmtLine(t, buf.String())
+	}
+	slogtest.Run(t, newHandler, result)
+}
+
+func TestLogfmtHandlerWithAttrsAndGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var h slog.Handler = New(Option{Writer: buf})
+	h = h.WithAttrs([]slog.Attr{slog.String("request_id", "r1")}).WithGroup("http").WithAttrs([]slog.Attr{slog.String("method", "GET")})
+
+	logger := slog.New(h)
+	logger.Info("done", "status", 200)
+	logger.Info("again")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if !strings.HasSuffix(lines[0], `msg=done request_id=r1 http.method=GET http.status=200`) {
+		t.Fatalf("unexpected attrs: %s", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], `msg=again request_id=r1 http.method=GET`) {
+		t.Fatalf("with attrs must persist: %s", lines[1])
+	}
+}
+
+// parseLogfmtLine 解析单行 logfmt，并按 "." 把键还原为嵌套 map。
+func parseLogfmtLine(t *testing.T, line string) map[string]any {
+	t.Helper()
+	out := map[string]any{}
+	line = strings.TrimSuffix(line, "\n")
+	for len(line) > 0 {
+		line = strings.TrimLeft(line, " ")
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			t.Fatalf("malformed logfmt: %q", line)
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+		var val string
+		if strings.HasPrefix(line, `"`) {
+			end := 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			unquoted, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				t.Fatalf("unquote %q: %v", line[:end+1], err)
+			}
+			val, line = unquoted, line[end+1:]
+		} else if sp := strings.IndexByte(line, ' '); sp >= 0 {
+			val, line = line[:sp], line[sp:]
+		} else {
+			val, line = line, ""
+		}
+
+		m := out
+		parts := strings.Split(key, ".")
+		for _, p := range parts[:len(parts)-1] {
+			sub, ok := m[p].(map[string]any)
+			if !ok {
+				sub = map[string]any{}
+				m[p] = sub
+			}
+			m = sub
+		}
+		m[parts[len(parts)-1]] = val
+	}
+	return out
+}
+
+func TestLogfmtHandlerReplaceAttrClearsKey(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := New(Option{Writer: buf, ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == "secret" {
+			a.Key = "" // 只清空键，值保持不变
+		}
+		return a
+	}})
+	slog.New(h).Info("m", "secret", "s3cr3t", "user", "alice", slog.Group("", slog.String("inline", "yes")))
+
+	out := strings.TrimSpace(buf.String())
+	if strings.Contains(out, "s3cr3t") || strings.Contains(out, " =") {
+		t.Fatalf("attr with cleared key must be dropped: %s", out)
+	}
+	if !strings.Contains(out, "user=alice") || !strings.Contains(out, "inline=yes") {
+		t.Fatalf("other attrs must be kept: %s", out)
+	}
+}
+
+func TestLogfmtLevelString(t *testing.T) {
+	const notice slog.Level = 2
+	if err := modules.RegisterLevel(modules.LevelDescriptor{Level: notice, Name: "Notice", Severity: 5}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	defer modules.UnregisterLevel(notice)
+
+	cases := map[slog.Level]string{
+		slog.LevelDebug - 4: "Trace",
+		slog.LevelDebug:     "Debug",
+		slog.LevelInfo:      "Info",
+		slog.LevelWarn:      "Warn",
+		slog.LevelError:     "Error",
+		slog.LevelError + 4: "Fatal",
+		slog.LevelWarn + 2:  "WARN+2",
+		notice:              "Notice",
+	}
+	for level, want := range cases {
+		if got := levelString(level); got != want {
+			t.Fatalf("levelString(%d) = %q, want %q", level, got, want)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package syslog
 
 import (
+	"io"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/darkit/slog/modules"
@@ -11,6 +13,7 @@ import (
 // SyslogAdapter Syslog模块适配器
 type SyslogAdapter struct {
 	*modules.BaseModule
+	mu     sync.Mutex
 	option *Option
 	sender *outputnet.Sender
 }
@@ -30,55 +33,131 @@ func (s *SyslogAdapter) Configure(config modules.Config) error {
 	}
 
 	var cfg struct {
-		Network string `json:"network"`
-		Addr    string `json:"addr"`
+		Network string `json:"network"` // tcp / udp / tls / unixgram
+		Addr    string `json:"addr"`    // unixgram 默认 /dev/log
 		Level   string `json:"level"`
 		Codec   string `json:"codec"`
+		Framing string `json:"framing"` // newline / octet-counting / none，默认按 network 选择
+
+		TLSCAFile     string `json:"tls_ca_file"`
+		TLSCertFile   string `json:"tls_cert_file"`
+		TLSKeyFile    string `json:"tls_key_file"`
+		TLSServerName string `json:"tls_server_name"`
+
+		// rfc5424 / rfc3164 编解码器的头部字段
+		Facility string `json:"facility"`
+		AppName  string `json:"app_name"`
+		Hostname string `json:"hostname"`
+		MsgID    string `json:"msgid"`
+		SDID     string `json:"sd_id"`
 	}
 
 	if err := config.Bind(&cfg); err != nil {
 		return err
 	}
 
-	// 配置syslog选项
+	// 先校验级别、编解码器与传输配置，全部通过后再替换，避免失败时留下半生效的状态
+	option := &Option{Level: slog.LevelDebug, Writer: s.option.Writer}
+	if level, ok := modules.ParseLevel(cfg.Level); ok {
+		option.Level = level
+	}
+	codec, ok := GetCodec(cfg.Codec)
+	if !ok && cfg.Codec != "" {
+		return errInvalidCodec
+	}
+	if _, header := codec.(HeaderCodec); header {
+		opts := HeaderOptions{
+			AppName:  cfg.AppName,
+			Hostname: cfg.Hostname,
+			MsgID:    cfg.MsgID,
+			SDID:     cfg.SDID,
+		}
+		if cfg.Facility != "" {
+			facility, err := ParseFacility(cfg.Facility)
+			if err != nil {
+				return err
+			}
+			opts.Facility = facility
+		}
+		switch codec.Name() {
+		case "rfc5424":
+			codec = NewRFC5424Codec(opts)
+		case "rfc3164":
+			codec = NewRFC3164Codec(opts)
+		}
+	}
+	option.Codec = codec
+
+	if cfg.Network == "unixgram" && cfg.Addr == "" {
+		cfg.Addr = DefaultLocalAddr
+	}
+	var sender *outputnet.Sender
 	if cfg.Network != "" && cfg.Addr != "" {
-		s.sender = outputnet.NewSender(outputnet.SenderOption{
-			Network:      cfg.Network,
-			Addr:         cfg.Addr,
-			Delimiter:    []byte("\n"),
-			DialTimeout:  3 * time.Second,
-			WriteTimeout: 3 * time.Second,
+		var err error
+		sender, option.Writer, err = newSender(cfg.Network, cfg.Addr, cfg.Framing, TLSOptions{
+			CAFile:     cfg.TLSCAFile,
+			CertFile:   cfg.TLSCertFile,
+			KeyFile:    cfg.TLSKeyFile,
+			ServerName: cfg.TLSServerName,
 		})
-		s.option.Writer = s.sender
-	}
-
-	switch cfg.Level {
-	case "debug":
-		s.option.Level = slog.LevelDebug
-	case "info":
-		s.option.Level = slog.LevelInfo
-	case "warn":
-		s.option.Level = slog.LevelWarn
-	case "error":
-		s.option.Level = slog.LevelError
-	case "":
-		s.option.Level = slog.LevelDebug
-	default:
-		s.option.Level = slog.LevelDebug
-	}
-	if codec, ok := GetCodec(cfg.Codec); ok {
-		s.option.Codec = codec
-	} else if cfg.Codec != "" {
-		return errInvalidCodec
+		if err != nil {
+			return err
+		}
 	}
 
-	// 创建处理器
-	if s.option.Writer != nil {
-		s.SetHandler(NewSyslogHandler(s.option.Writer, s.option))
+	s.mu.Lock()
+	prev := s.sender
+	if sender != nil {
+		s.sender = sender
+	}
+	s.option = option
+	if option.Writer != nil {
+		s.SetHandler(NewSyslogHandler(option.Writer, option))
+	}
+	s.mu.Unlock()
+
+	// 旧发送器在新 handler 生效后关闭
+	if sender != nil && prev != nil {
+		_ = prev.Close()
 	}
 	return nil
 }
 
+// newSender 按网络类型创建发送器与写入目标；tls 通过 TLSDial 建连，octet-counting 分帧包装在发送器外层。
+func newSender(network, addr, framing string, tlsOpts TLSOptions) (*outputnet.Sender, io.Writer, error) {
+	frame := defaultFraming(network)
+	if framing != "" {
+		f, err := ParseFraming(framing)
+		if err != nil {
+			return nil, nil, err
+		}
+		frame = f
+	}
+
+	opt := outputnet.SenderOption{
+		Network:      network,
+		Addr:         addr,
+		DialTimeout:  3 * time.Second,
+		WriteTimeout: 3 * time.Second,
+	}
+	if network == "tls" {
+		tlsConfig, err := tlsOpts.Config()
+		if err != nil {
+			return nil, nil, err
+		}
+		opt.Dial = TLSDial(tlsConfig)
+	}
+	if frame == FramingNewline {
+		opt.Delimiter = []byte("\n")
+	}
+
+	sender := outputnet.NewSender(opt)
+	if frame == FramingOctetCounting {
+		return sender, NewOctetCountingWriter(sender), nil
+	}
+	return sender, sender, nil
+}
+
 // init 注册syslog模块工厂
 func init() {
 	if err := modules.RegisterFactory("syslog", func(config modules.Config) (modules.Module, error) {
@@ -12,6 +12,7 @@ import (
 
 	svr "github.com/darkit/slog"
 	"github.com/darkit/slog/internal/common"
+	"github.com/darkit/slog/modules"
 )
 
 var errInvalidCodec = errors.New("syslog: invalid codec")
@@ -32,6 +33,8 @@ var globalCodecs = &codecRegistry{codecs: map[string]Codec{}}
 func init() {
 	_ = RegisterCodec(defaultCodec{})
 	_ = RegisterCodec(jsonCodec{})
+	_ = RegisterCodec(NewRFC5424Codec(HeaderOptions{}))
+	_ = RegisterCodec(NewRFC3164Codec(HeaderOptions{}))
 }
 
 func RegisterCodec(codec Codec) error {
@@ -69,7 +72,7 @@ func (c defaultCodec) Encode(_ context.Context, record *slog.Record, attrs []slo
 		"logger.name":     svr.Name,
 		"logger.version":  svr.Version,
 		"timestamp":       record.Time.UTC(),
-		"level":           record.Level.String(),
+		"level":           levelName(record.Level),
 		"message":         record.Message,
 		defaultContextKey: common.AttrsToMap(attrs...),
 	}
@@ -83,7 +86,7 @@ func (c jsonCodec) Name() string { return "json" }
 func (c jsonCodec) Encode(_ context.Context, record *slog.Record, attrs []slog.Attr, groups []string) ([]byte, error) {
 	flat := common.AttrsToMap(common.AppendRecordAttrsToAttrs(attrs, groups, record)...)
 	payload := map[string]any{
-		"level":   record.Level.String(),
+		"level":   levelName(record.Level),
 		"message": record.Message,
 	}
 	if !record.Time.IsZero() {
@@ -93,6 +96,14 @@ func (c jsonCodec) Encode(_ context.Context, record *slog.Record, attrs []slog.A
 	return json.Marshal(payload)
 }
 
+// levelName 内置与未注册级别沿用 slog 默认格式（如 "DEBUG-4"），自定义级别取大写的注册名。
+func levelName(level slog.Level) string {
+	if d, ok := modules.LookupCustomLevel(level); ok {
+		return strings.ToUpper(d.Name)
+	}
+	return level.String()
+}
+
 var (
 	defaultContextKey = "extra"
 	defaultErrorKeys  = []string{"error", "err"}
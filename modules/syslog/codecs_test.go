@@ -112,3 +112,24 @@ func TestSyslogAdapter_InvalidCodec(t *testing.T) {
 		t.Fatal("expected invalid codec error")
 	}
 }
+
+func TestLevelName_BuiltinSpellings(t *testing.T) {
+	const notice slog.Level = 2
+	if err := modules.RegisterLevel(modules.LevelDescriptor{Level: notice, Name: "Notice", Severity: 5}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	defer modules.UnregisterLevel(notice)
+
+	cases := map[slog.Level]string{
+		slog.LevelDebug - 4: "DEBUG-4",
+		slog.LevelInfo:      "INFO",
+		slog.LevelError + 4: "ERROR+4",
+		slog.LevelWarn + 2:  "WARN+2",
+		notice:              "NOTICE",
+	}
+	for level, want := range cases {
+		if got := levelName(level); got != want {
+			t.Fatalf("levelName(%d) = %q, want %q", level, got, want)
+		}
+	}
+}
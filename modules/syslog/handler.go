@@ -74,8 +74,11 @@ func (h *SyslogHandler) Handle(ctx context.Context, record slog.Record) error {
 		return err
 	}
 
+	if _, ok := h.option.Codec.(HeaderCodec); !ok {
+		payload = append([]byte(ceePrefix), payload...)
+	}
 	modules.RunAsync("syslog", func() error {
-		_, err := h.option.Writer.Write(append([]byte(ceePrefix), payload...))
+		_, err := h.option.Writer.Write(payload)
 		return err
 	})
 	return nil
@@ -0,0 +1,312 @@
+package syslog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darkit/slog/internal/common"
+	"github.com/darkit/slog/modules"
+)
+
+// Facility 是 syslog 设施编号（RFC 5424 表 1）。
+type Facility int
+
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthpriv
+	FacilityFtp
+)
+
+const (
+	FacilityLocal0 Facility = iota + 16
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+var errInvalidFacility = errors.New("syslog: invalid facility")
+
+var facilityNames = map[string]Facility{
+	"kern": FacilityKern, "user": FacilityUser, "mail": FacilityMail, "daemon": FacilityDaemon,
+	"auth": FacilityAuth, "syslog": FacilitySyslog, "lpr": FacilityLpr, "news": FacilityNews,
+	"uucp": FacilityUucp, "cron": FacilityCron, "authpriv": FacilityAuthpriv, "ftp": FacilityFtp,
+	"local0": FacilityLocal0, "local1": FacilityLocal1, "local2": FacilityLocal2, "local3": FacilityLocal3,
+	"local4": FacilityLocal4, "local5": FacilityLocal5, "local6": FacilityLocal6, "local7": FacilityLocal7,
+}
+
+// ParseFacility 按名称（如 "local0"）或数字解析设施。
+func ParseFacility(name string) (Facility, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if f, ok := facilityNames[name]; ok {
+		return f, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= 0 && n <= 23 {
+		return Facility(n), nil
+	}
+	return 0, errInvalidFacility
+}
+
+// DefaultSDID 是结构化数据元素的默认 SD-ID，使用 RFC 5612 文档保留的企业号。
+const DefaultSDID = "slog@32473"
+
+// HeaderOptions 配置带 syslog 头部的编解码器。
+type HeaderOptions struct {
+	// Facility 为零值（kern）时视为 FacilityUser；应用日志不应使用 kern。
+	Facility Facility
+	AppName  string // 默认取可执行文件名
+	Hostname string // 默认取 os.Hostname
+	MsgID    string // 仅 RFC 5424，默认 "-"
+	SDID     string // 仅 RFC 5424，属性所在 SD 元素的 ID，默认 DefaultSDID
+}
+
+func (o HeaderOptions) withDefaults() HeaderOptions {
+	if o.Facility == FacilityKern {
+		o.Facility = FacilityUser
+	}
+	if o.AppName == "" && len(os.Args) > 0 {
+		o.AppName = filepath.Base(os.Args[0])
+	}
+	if o.Hostname == "" {
+		o.Hostname, _ = os.Hostname()
+	}
+	if o.SDID == "" {
+		o.SDID = DefaultSDID
+	}
+	return o
+}
+
+// HeaderCodec 是自带 syslog 头部的 Codec，handler 原样发送其输出而不加 "@cee: " 前缀。
+type HeaderCodec interface {
+	Codec
+	SyslogHeader()
+}
+
+// NewRFC5424Codec 创建 RFC 5424 编解码器：
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID key="value" ...] MSG
+//
+// 属性展开为点分键名写入一个 SD 元素，无属性时为 "-"。
+func NewRFC5424Codec(opts HeaderOptions) HeaderCodec {
+	return &rfc5424Codec{opts: opts.withDefaults(), pid: strconv.Itoa(os.Getpid())}
+}
+
+// NewRFC3164Codec 创建 BSD syslog（RFC 3164）编解码器：
+//
+//	<PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG key=value ...
+//
+// 时间使用记录自身的时区；BSD 格式没有结构化数据，属性以 key=value 追加在消息后。
+func NewRFC3164Codec(opts HeaderOptions) HeaderCodec {
+	return &rfc3164Codec{opts: opts.withDefaults(), pid: strconv.Itoa(os.Getpid())}
+}
+
+type rfc5424Codec struct {
+	opts HeaderOptions
+	pid  string
+}
+
+func (c *rfc5424Codec) Name() string  { return "rfc5424" }
+func (c *rfc5424Codec) SyslogHeader() {}
+
+func (c *rfc5424Codec) Encode(_ context.Context, record *slog.Record, attrs []slog.Attr, groups []string) ([]byte, error) {
+	var buf bytes.Buffer
+	writePRI(&buf, c.opts.Facility, record.Level)
+	buf.WriteString("1 ")
+	if record.Time.IsZero() {
+		buf.WriteByte('-')
+	} else {
+		buf.WriteString(record.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+	}
+	for _, field := range []struct {
+		val string
+		max int
+	}{
+		{c.opts.Hostname, 255},
+		{c.opts.AppName, 48},
+		{c.pid, 128},
+		{c.opts.MsgID, 32},
+	} {
+		buf.WriteByte(' ')
+		buf.WriteString(headerField(field.val, field.max))
+	}
+
+	buf.WriteByte(' ')
+	params := flattenAttrs(common.AppendRecordAttrsToAttrs(attrs, groups, record))
+	if len(params) == 0 {
+		buf.WriteByte('-')
+	} else {
+		buf.WriteByte('[')
+		buf.WriteString(sdName(c.opts.SDID))
+		for _, p := range params {
+			buf.WriteByte(' ')
+			buf.WriteString(sdName(p.key))
+			buf.WriteString(`="`)
+			writeSDValue(&buf, p.val)
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(']')
+	}
+
+	if record.Message != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(record.Message)
+	}
+	return buf.Bytes(), nil
+}
+
+type rfc3164Codec struct {
+	opts HeaderOptions
+	pid  string
+}
+
+func (c *rfc3164Codec) Name() string  { return "rfc3164" }
+func (c *rfc3164Codec) SyslogHeader() {}
+
+func (c *rfc3164Codec) Encode(_ context.Context, record *slog.Record, attrs []slog.Attr, groups []string) ([]byte, error) {
+	var buf bytes.Buffer
+	writePRI(&buf, c.opts.Facility, record.Level)
+	ts := record.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	buf.WriteString(ts.Format(time.Stamp))
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(c.opts.Hostname, 255))
+	buf.WriteByte(' ')
+	buf.WriteString(bsdTag(c.opts.AppName))
+	buf.WriteByte('[')
+	buf.WriteString(c.pid)
+	buf.WriteString("]: ")
+	buf.WriteString(record.Message)
+
+	for _, p := range flattenAttrs(common.AppendRecordAttrsToAttrs(attrs, groups, record)) {
+		buf.WriteByte(' ')
+		buf.WriteString(p.key)
+		buf.WriteByte('=')
+		if p.val == "" || strings.ContainsAny(p.val, " =\"\n") {
+			buf.WriteString(strconv.Quote(p.val))
+		} else {
+			buf.WriteString(p.val)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writePRI 写入 <PRI>，PRI = facility*8 + severity。
+func writePRI(buf *bytes.Buffer, f Facility, level slog.Level) {
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(int(f)*8 + modules.LevelSeverity(level)))
+	buf.WriteByte('>')
+}
+
+// headerField 把头部字段限制为可打印 ASCII 且不超过 max 字节，空值输出 NILVALUE "-"。
+func headerField(s string, max int) string {
+	if s == "" {
+		return "-"
+	}
+	b := make([]byte, 0, min(len(s), max))
+	for i := 0; i < len(s) && len(b) < max; i++ {
+		c := s[i]
+		if c < 33 || c > 126 {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// sdName 把 SD-ID 或 PARAM-NAME 限制为 32 个可打印 ASCII 字符，并替换 '=', ']', '"' 与空格。
+func sdName(s string) string {
+	b := []byte(headerField(s, 32))
+	for i, c := range b {
+		if c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// writeSDValue 按 RFC 5424 6.3.3 转义 PARAM-VALUE 中的 '"', '\' 与 ']'。
+func writeSDValue(buf *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\\', ']':
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(s[i])
+	}
+}
+
+// bsdTag 返回 RFC 3164 TAG：取开头最多 32 个字母、数字或 "-_." 字符。
+func bsdTag(s string) string {
+	end := 0
+	for end < len(s) && end < 32 {
+		c := s[end]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			break
+		}
+		end++
+	}
+	if end == 0 {
+		return "-"
+	}
+	return s[:end]
+}
+
+type sdParam struct {
+	key string
+	val string
+}
+
+// flattenAttrs 把分组属性展开为点分键名，跳过空属性与空分组。
+func flattenAttrs(attrs []slog.Attr) []sdParam {
+	var out []sdParam
+	var walk func(prefix string, attrs []slog.Attr)
+	walk = func(prefix string, attrs []slog.Attr) {
+		for _, a := range attrs {
+			a.Value = a.Value.Resolve()
+			if a.Equal(slog.Attr{}) {
+				continue
+			}
+			key := a.Key
+			if prefix != "" && key != "" {
+				key = prefix + "." + key
+			} else if key == "" {
+				key = prefix
+			}
+			if a.Value.Kind() == slog.KindGroup {
+				walk(key, a.Value.Group())
+				continue
+			}
+			out = append(out, sdParam{key: key, val: paramValue(a.Value)})
+		}
+	}
+	walk("", attrs)
+	return out
+}
+
+func paramValue(v slog.Value) string {
+	if v.Kind() == slog.KindTime {
+		return v.Time().Format(time.RFC3339Nano)
+	}
+	return v.String()
+}
@@ -0,0 +1,124 @@
+package syslog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darkit/slog/modules"
+)
+
+func TestRFC5424Codec_Encode(t *testing.T) {
+	codec := NewRFC5424Codec(HeaderOptions{
+		Facility: FacilityLocal0,
+		AppName:  "billing",
+		Hostname: "api-01",
+		MsgID:    "ORDER",
+	})
+	rec := slog.Record{Time: time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC), Level: slog.LevelWarn, Message: "slow request"}
+	rec.AddAttrs(slog.String("path", `/a"b]c\d`), slog.Group("http", slog.Int("status", 504)))
+
+	out, err := codec.Encode(context.Background(), &rec, []slog.Attr{slog.String("user", "bob")}, nil)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	// local0(16)*8 + warning(4) = 132
+	want := `<132>1 2024-01-02T03:04:05.123456Z api-01 billing ` + strconv.Itoa(os.Getpid()) +
+		` ORDER [slog@32473 user="bob" path="/a\"b\]c\\d" http.status="504"] slow request`
+	if string(out) != want {
+		t.Fatalf("unexpected output:\n got %s\nwant %s", out, want)
+	}
+}
+
+func TestRFC5424Codec_NilValues(t *testing.T) {
+	codec := NewRFC5424Codec(HeaderOptions{AppName: "app", Hostname: "host", SDID: "meta@1 bad"})
+	rec := slog.Record{Level: slog.LevelError}
+	out, _ := codec.Encode(context.Background(), &rec, nil, nil)
+	// 默认 user(1)*8 + err(3) = 11；零时间、空 MSGID 与无属性均为 "-"，消息为空时不输出尾部空格。
+	want := "<11>1 - host app " + strconv.Itoa(os.Getpid()) + " - -"
+	if string(out) != want {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	rec.AddAttrs(slog.String("k=v ey", "x"))
+	out, _ = codec.Encode(context.Background(), &rec, nil, nil)
+	if !strings.HasSuffix(string(out), `[meta@1_bad k_v_ey="x"]`) {
+		t.Fatalf("SD names should be sanitized: %q", out)
+	}
+}
+
+func TestRFC3164Codec_Encode(t *testing.T) {
+	codec := NewRFC3164Codec(HeaderOptions{Facility: FacilityDaemon, AppName: "worker", Hostname: "node1"})
+	rec := slog.Record{Time: time.Date(2024, 3, 7, 8, 9, 10, 0, time.UTC), Level: slog.LevelInfo, Message: "job done"}
+	rec.AddAttrs(slog.Int("id", 7), slog.String("note", "two words"), slog.Any("err", errors.New("boom")))
+
+	out, err := codec.Encode(context.Background(), &rec, nil, []string{"job"})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	// daemon(3)*8 + info(6) = 30；RFC 3164 日期不足两位时以空格补齐。
+	want := `<30>Mar  7 08:09:10 node1 worker[` + strconv.Itoa(os.Getpid()) + `]: job done job.id=7 job.note="two words" job.err=boom`
+	if string(out) != want {
+		t.Fatalf("unexpected output:\n got %s\nwant %s", out, want)
+	}
+}
+
+func TestHeaderCodecs_Registered(t *testing.T) {
+	for _, name := range []string{"rfc5424", "rfc3164"} {
+		codec, ok := GetCodec(name)
+		if !ok {
+			t.Fatalf("%s codec missing", name)
+		}
+		if _, ok := codec.(HeaderCodec); !ok {
+			t.Fatalf("%s codec should implement HeaderCodec", name)
+		}
+	}
+}
+
+func TestSyslogHandler_HeaderCodecSkipsCEEPrefix(t *testing.T) {
+	w := newAsyncCaptureWriter()
+	h := NewSyslogHandler(w, &Option{Codec: NewRFC5424Codec(HeaderOptions{AppName: "app", Hostname: "host"})})
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hi", 0)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	select {
+	case <-w.done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for write")
+	}
+	if out := w.String(); !strings.HasPrefix(out, "<14>1 ") || strings.Contains(out, ceePrefix) {
+		t.Fatalf("unexpected payload: %q", out)
+	}
+}
+
+func TestSyslogAdapter_ConfigureHeaderCodec(t *testing.T) {
+	adapter := NewSyslogAdapter()
+	err := adapter.Configure(modules.Config{
+		"network":  "udp",
+		"addr":     "127.0.0.1:9999",
+		"codec":    "rfc5424",
+		"facility": "local3",
+		"app_name": "svc",
+		"hostname": "h1",
+		"msgid":    "AUDIT",
+	})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	rec := slog.Record{Level: slog.LevelInfo, Message: "m"}
+	out, _ := adapter.option.Codec.Encode(context.Background(), &rec, nil, nil)
+	// local3(19)*8 + info(6) = 158
+	if !strings.HasPrefix(string(out), "<158>1 - h1 svc ") || !strings.Contains(string(out), " AUDIT - m") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	err = NewSyslogAdapter().Configure(modules.Config{"codec": "rfc3164", "facility": "local9"})
+	if err == nil {
+		t.Fatal("expected invalid facility error")
+	}
+}
@@ -0,0 +1,128 @@
+package syslog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	stdnet "net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	outputnet "github.com/darkit/slog/modules/output/net"
+)
+
+// DefaultLocalAddr 是本机 syslog 守护进程的 unixgram 套接字。
+const DefaultLocalAddr = "/dev/log"
+
+// Framing 决定流式传输（tcp / tls）中报文的分帧方式。
+type Framing string
+
+const (
+	// FramingNewline 以换行结尾（RFC 6587 non-transparent framing），消息本身不能含换行。
+	FramingNewline Framing = "newline"
+	// FramingOctetCounting 以 "长度 空格" 开头（RFC 6587 octet-counting），可承载多行消息，TLS 默认使用。
+	FramingOctetCounting Framing = "octet-counting"
+	// FramingNone 不分帧，用于 udp / unixgram 等数据报传输。
+	FramingNone Framing = "none"
+)
+
+var (
+	errInvalidFraming = errors.New("syslog: invalid framing")
+	errInvalidCA      = errors.New("syslog: no certificates found in CA file")
+	errCertKeyPair    = errors.New("syslog: tls cert and key must be set together")
+)
+
+// ParseFraming 解析分帧方式名称，"octet" 为 octet-counting 的简写。
+func ParseFraming(name string) (Framing, error) {
+	switch f := Framing(strings.ToLower(strings.TrimSpace(name))); f {
+	case FramingNewline, FramingOctetCounting, FramingNone:
+		return f, nil
+	case "octet":
+		return FramingOctetCounting, nil
+	default:
+		return "", errInvalidFraming
+	}
+}
+
+// defaultFraming 返回网络类型的默认分帧：tls 为 octet-counting，数据报为 none，其余为 newline。
+func defaultFraming(network string) Framing {
+	switch network {
+	case "tls":
+		return FramingOctetCounting
+	case "udp", "udp4", "udp6", "unixgram":
+		return FramingNone
+	default:
+		return FramingNewline
+	}
+}
+
+// octetCountingWriter 为每条报文加上 "LEN SP" 前缀后一次写入。
+type octetCountingWriter struct {
+	w io.Writer
+}
+
+// NewOctetCountingWriter 返回按 RFC 6587 octet-counting 分帧的 writer，每次 Write 视为一条报文。
+func NewOctetCountingWriter(w io.Writer) io.Writer {
+	return &octetCountingWriter{w: w}
+}
+
+func (o *octetCountingWriter) Write(p []byte) (int, error) {
+	frame := make([]byte, 0, len(p)+8)
+	frame = strconv.AppendInt(frame, int64(len(p)), 10)
+	frame = append(frame, ' ')
+	frame = append(frame, p...)
+	if _, err := o.w.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// TLSOptions 配置 syslog over TLS（RFC 5425）。
+type TLSOptions struct {
+	CAFile     string // PEM 格式的 CA 证书，为空时使用系统根证书
+	CertFile   string // 客户端证书，需与 KeyFile 同时设置
+	KeyFile    string
+	ServerName string // 校验服务端证书使用的名称，为空时取地址中的主机名
+}
+
+// Config 按选项构建 tls.Config。
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: o.ServerName,
+	}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("syslog: read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errInvalidCA
+		}
+		cfg.RootCAs = pool
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, errCertKeyPair
+	}
+	if o.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("syslog: load client cert: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// TLSDial 返回通过 TLS 建连的 outputnet.DialFunc，底层网络固定为 tcp。
+func TLSDial(cfg *tls.Config) outputnet.DialFunc {
+	return func(_, addr string, timeout time.Duration) (stdnet.Conn, error) {
+		d := &tls.Dialer{NetDialer: &stdnet.Dialer{Timeout: timeout}, Config: cfg}
+		return d.Dial("tcp", addr)
+	}
+}
@@ -0,0 +1,373 @@
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darkit/slog/modules"
+)
+
+func TestOctetCountingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewOctetCountingWriter(&buf)
+	if n, err := w.Write([]byte("line1\nline2")); err != nil || n != 11 {
+		t.Fatalf("write: n=%d err=%v", n, err)
+	}
+	_, _ = w.Write([]byte("é"))
+	if got := buf.String(); got != "11 line1\nline22 é" {
+		t.Fatalf("unexpected frames: %q", got)
+	}
+}
+
+func TestParseFraming(t *testing.T) {
+	if f, err := ParseFraming("octet"); err != nil || f != FramingOctetCounting {
+		t.Fatalf("ParseFraming(octet) = %v, %v", f, err)
+	}
+	if _, err := ParseFraming("crlf"); err == nil {
+		t.Fatal("expected error for unknown framing")
+	}
+	if err := NewSyslogAdapter().Configure(modules.Config{"network": "tcp", "addr": "127.0.0.1:1", "framing": "crlf"}); err == nil {
+		t.Fatal("expected adapter error for unknown framing")
+	}
+}
+
+func TestSyslogAdapter_TCPOctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	frames := readOctetFrames(t, ln)
+
+	adapter := NewSyslogAdapter()
+	if err := adapter.Configure(modules.Config{
+		"network": "tcp",
+		"addr":    ln.Addr().String(),
+		"codec":   "rfc5424",
+		"framing": "octet-counting",
+	}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	slog.New(adapter.Handler()).Info("first line\nsecond line")
+
+	select {
+	case frame := <-frames:
+		if !strings.HasPrefix(frame, "<14>1 ") || !strings.HasSuffix(frame, "first line\nsecond line") {
+			t.Fatalf("unexpected frame: %q", frame)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for frame")
+	}
+}
+
+func TestSyslogAdapter_TLS(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, caFile := writeTestCA(t, dir)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{serverCert}})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	frames := readOctetFrames(t, ln)
+
+	adapter := NewSyslogAdapter()
+	if err := adapter.Configure(modules.Config{
+		"network":         "tls",
+		"addr":            ln.Addr().String(),
+		"codec":           "rfc5424",
+		"tls_ca_file":     caFile,
+		"tls_server_name": "syslog.test",
+	}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	slog.New(adapter.Handler()).Warn("over tls")
+
+	select {
+	case frame := <-frames:
+		if !strings.HasPrefix(frame, "<12>1 ") || !strings.HasSuffix(frame, " over tls") {
+			t.Fatalf("unexpected frame: %q", frame)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for frame")
+	}
+
+	if err := NewSyslogAdapter().Configure(modules.Config{"network": "tls", "addr": "127.0.0.1:1", "tls_ca_file": filepath.Join(dir, "missing.pem")}); err == nil {
+		t.Fatal("expected error for missing CA file")
+	}
+	if err := NewSyslogAdapter().Configure(modules.Config{"network": "tls", "addr": "127.0.0.1:1", "tls_cert_file": caFile}); err == nil {
+		t.Fatal("expected error for cert without key")
+	}
+}
+
+func TestSyslogAdapter_Unixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not supported: %v", err)
+	}
+	defer conn.Close()
+
+	adapter := NewSyslogAdapter()
+	if err := adapter.Configure(modules.Config{
+		"network": "unixgram",
+		"addr":    path,
+		"codec":   "rfc3164",
+	}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	slog.New(adapter.Handler()).Error("local delivery")
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	// 数据报传输不分帧，报文末尾没有换行。
+	if got := string(buf[:n]); !strings.HasPrefix(got, "<11>") || !strings.HasSuffix(got, "]: local delivery") {
+		t.Fatalf("unexpected datagram: %q", got)
+	}
+
+	defaults := NewSyslogAdapter()
+	if err := defaults.Configure(modules.Config{"network": "unixgram"}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	if defaults.sender == nil {
+		t.Fatalf("unixgram without addr should default to %s", DefaultLocalAddr)
+	}
+}
+
+func TestSyslogAdapter_TLSClientCert(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, caFile := writeTestCA(t, dir)
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		t.Fatalf("read CA: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caPEM)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	frames := readOctetFrames(t, ln)
+
+	adapter := NewSyslogAdapter()
+	if err := adapter.Configure(modules.Config{
+		"network":         "tls",
+		"addr":            ln.Addr().String(),
+		"codec":           "rfc5424",
+		"tls_ca_file":     caFile,
+		"tls_cert_file":   filepath.Join(dir, "client.pem"),
+		"tls_key_file":    filepath.Join(dir, "client-key.pem"),
+		"tls_server_name": "syslog.test",
+	}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	slog.New(adapter.Handler()).Warn("mutual tls")
+
+	select {
+	case frame := <-frames:
+		if !strings.HasSuffix(frame, " mutual tls") {
+			t.Fatalf("unexpected frame: %q", frame)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for frame")
+	}
+
+	if err := NewSyslogAdapter().Configure(modules.Config{
+		"network":       "tls",
+		"addr":          "127.0.0.1:1",
+		"tls_cert_file": filepath.Join(dir, "client.pem"),
+		"tls_key_file":  caFile,
+	}); err == nil {
+		t.Fatal("expected error for mismatched client key")
+	}
+}
+
+func TestSyslogAdapter_ReconfigureSwapsSender(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	accepted, closed := make(chan struct{}), make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		close(accepted)
+		_, _ = io.Copy(io.Discard, conn)
+		close(closed)
+	}()
+
+	adapter := NewSyslogAdapter()
+	if err := adapter.Configure(modules.Config{"network": "tcp", "addr": ln.Addr().String(), "level": "info"}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	slog.New(adapter.Handler()).Info("connect")
+	select {
+	case <-accepted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for connection")
+	}
+	handler, sender := adapter.Handler(), adapter.sender
+
+	// 编解码器或 facility 非法时不得替换发送器，也不得改动正在使用的 handler。
+	for _, bad := range []modules.Config{
+		{"network": "tcp", "addr": "127.0.0.1:1", "codec": "nope"},
+		{"network": "tcp", "addr": "127.0.0.1:1", "codec": "rfc5424", "facility": "nope"},
+		{"network": "tcp", "addr": "127.0.0.1:1", "level": "debug", "framing": "nope"},
+	} {
+		if err := adapter.Configure(bad); err == nil {
+			t.Fatalf("expected error for %v", bad)
+		}
+	}
+	if adapter.Handler() != handler || adapter.sender != sender || handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("failed configure must leave the previous state untouched")
+	}
+
+	if err := adapter.Configure(modules.Config{"network": "udp", "addr": "127.0.0.1:1"}); err != nil {
+		t.Fatalf("reconfigure: %v", err)
+	}
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("previous sender must be closed after reconfigure")
+	}
+	if adapter.sender == sender {
+		t.Fatal("expected a new sender")
+	}
+}
+
+// readOctetFrames 接受一个连接并按 octet-counting 读取报文。
+func readOctetFrames(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+	frames := make(chan string, 4)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			size, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(size))
+			if err != nil {
+				return
+			}
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			frames <- string(msg)
+		}
+	}()
+	return frames
+}
+
+// writeTestCA 生成自签名的 CA、服务端证书与客户端证书，返回服务端证书与 CA 文件路径；
+// 客户端证书与私钥写入 dir 下的 client.pem / client-key.pem。
+func writeTestCA(t *testing.T, dir string) (tls.Certificate, string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create CA: %v", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	srvKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	srvTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "syslog.test"},
+		DNSNames:     []string{"syslog.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	srvDER, err := x509.CreateCertificate(rand.Reader, srvTmpl, caCert, &srvKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create server cert: %v", err)
+	}
+
+	cliKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	cliTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "syslog client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cliDER, err := x509.CreateCertificate(rand.Reader, cliTmpl, caCert, &cliKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create client cert: %v", err)
+	}
+	cliKeyDER, err := x509.MarshalECPrivateKey(cliKey)
+	if err != nil {
+		t.Fatalf("marshal client key: %v", err)
+	}
+
+	caFile := filepath.Join(dir, "ca.pem")
+	for name, block := range map[string]*pem.Block{
+		caFile:                               {Type: "CERTIFICATE", Bytes: caDER},
+		filepath.Join(dir, "client.pem"):     {Type: "CERTIFICATE", Bytes: cliDER},
+		filepath.Join(dir, "client-key.pem"): {Type: "EC PRIVATE KEY", Bytes: cliKeyDER},
+	} {
+		if err := os.WriteFile(name, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return tls.Certificate{Certificate: [][]byte{srvDER}, PrivateKey: srvKey}, caFile
+}
@@ -0,0 +1,152 @@
+package slog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+)
+
+// PanicKey / PanicTypeKey 是 Recover 系列方法记录的 panic 值与类型属性名。
+const (
+	PanicKey     = "panic"
+	PanicTypeKey = "panic_type"
+)
+
+// SetPanicLevel 设置 Recover / Go / HTTPMiddleware 记录 panic 的级别。
+func (c *Config) SetPanicLevel(level Level) {
+	if c == nil {
+		return
+	}
+	c.PanicLevel = level
+}
+
+// Recover 捕获当前 goroutine 的 panic 并记录值、类型与调用栈，需直接用于 defer：
+//
+//	defer logger.Recover(ctx, "worker crashed")
+//
+// Config.RePanic 为 true 时记录后重新抛出。
+func (l *Logger) Recover(ctx context.Context, msg string, args ...any) {
+	if rec := recover(); rec != nil {
+		l.logPanic(ctx, rec, msg, args...)
+	}
+}
+
+// Go 在新 goroutine 中执行 fn，panic 会被捕获并以 goroutine=name 记录。
+func (l *Logger) Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				l.logPanic(ctx, rec, "goroutine panic", "goroutine", name)
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+// HTTPMiddleware 捕获 handler 中的 panic，记录请求方法与路径后返回 500（响应头未写出时）。
+// http.ErrAbortHandler 按约定直接透传，不记录。
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tw := &panicResponseWriter{ResponseWriter: w}
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			l.logPanic(r.Context(), rec, "http handler panic", "method", r.Method, "path", r.URL.Path)
+			// 响应头已写出时无法再改状态码，只记录不响应。
+			if !tw.wroteHeader {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(tw, r)
+	})
+}
+
+// panicResponseWriter 记录响应头是否已写出，并转发 Flusher / Hijacker / Pusher，
+// 保证 websocket 升级与流式响应在中间件后仍可用。
+type panicResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *panicResponseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *panicResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *panicResponseWriter) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack 接管连接后不再写 500 响应。
+func (w *panicResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+func (w *panicResponseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter。
+func (w *panicResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// logPanic 记录 panic；调用栈从 panic 发生处开始（Logger 方法帧已由默认跳过前缀过滤）。
+func (l *Logger) logPanic(ctx context.Context, rec any, msg string, args ...any) {
+	if l == nil {
+		l = Default()
+	}
+	level := LevelError
+	rePanic := false
+	depth := defaultStacktraceDepth
+	if l.config != nil {
+		if l.config.PanicLevel != nil {
+			level = l.config.PanicLevel.Level()
+		}
+		if l.config.StacktraceDepth > 0 {
+			depth = l.config.StacktraceDepth
+		}
+		rePanic = l.config.RePanic
+	}
+
+	attrs := make([]any, 0, len(args)+3)
+	attrs = append(attrs, args...)
+	attrs = append(attrs,
+		slog.Any(PanicKey, rec),
+		slog.String(PanicTypeKey, fmt.Sprintf("%T", rec)),
+		slog.Any(StacktraceKey, captureStacktrace(3, depth)),
+	)
+	if ctx == nil {
+		ctx = l.ctx
+	}
+	// 消息原样使用，避免其中的 % 动词把 panic 属性当作格式化参数吞掉。
+	l.emitRecord(level, ctx, msg, recordVerbatim, attrs)
+
+	if rePanic {
+		panic(rec)
+	}
+}
@@ -0,0 +1,256 @@
+package slog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newPanicTestLogger(w io.Writer, configure func(*Config)) *Logger {
+	resetForTest()
+	cfg := DefaultConfig()
+	cfg.SetEnableText(false)
+	cfg.SetEnableJSON(true)
+	if configure != nil {
+		configure(cfg)
+	}
+	return NewLoggerWithConfig(w, cfg)
+}
+
+type panicPayload struct {
+	Level     string       `json:"level"`
+	Msg       string       `json:"msg"`
+	Panic     any          `json:"panic"`
+	PanicType string       `json:"panic_type"`
+	Goroutine string       `json:"goroutine"`
+	Path      string       `json:"path"`
+	Stack     []StackFrame `json:"stacktrace"`
+}
+
+func decodePanicPayload(t *testing.T, buf *bytes.Buffer) panicPayload {
+	t.Helper()
+	var p panicPayload
+	if err := json.Unmarshal(buf.Bytes(), &p); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	return p
+}
+
+func panicky() {
+	panic("boom")
+}
+
+func TestLoggerRecover(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newPanicTestLogger(&buf, func(c *Config) { c.SetPanicLevel(LevelWarn) })
+
+	func() {
+		defer logger.Recover(context.Background(), "worker crashed", "job", 1)
+		panicky()
+	}()
+
+	p := decodePanicPayload(t, &buf)
+	if p.Level != "Warn" || p.Msg != "worker crashed" || p.Panic != "boom" || p.PanicType != "string" {
+		t.Fatalf("unexpected payload: %s", buf.String())
+	}
+	if len(p.Stack) == 0 || p.Stack[0].Function != "github.com/darkit/slog.panicky" {
+		t.Fatalf("stack must start at panic site: %+v", p.Stack)
+	}
+}
+
+func TestLoggerRecoverRePanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newPanicTestLogger(&buf, func(c *Config) { c.RePanic = true })
+
+	defer func() {
+		if rec := recover(); rec != "boom" {
+			t.Fatalf("expected re-panic, got %v", rec)
+		}
+		if !strings.Contains(buf.String(), `"panic":"boom"`) {
+			t.Fatalf("panic must be logged before re-panic: %s", buf.String())
+		}
+	}()
+	func() {
+		defer logger.Recover(context.Background(), "crash")
+		panicky()
+	}()
+}
+
+type notifyWriter struct {
+	buf  bytes.Buffer
+	done chan struct{}
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	n, err := w.buf.Write(p)
+	close(w.done)
+	return n, err
+}
+
+func TestLoggerGo(t *testing.T) {
+	w := &notifyWriter{done: make(chan struct{})}
+	logger := newPanicTestLogger(w, nil)
+
+	logger.Go(context.Background(), "consumer", func(context.Context) {
+		panicky()
+	})
+	<-w.done
+
+	p := decodePanicPayload(t, &w.buf)
+	if p.Goroutine != "consumer" || p.Level != "Error" || p.Panic != "boom" {
+		t.Fatalf("unexpected payload: %s", w.buf.String())
+	}
+}
+
+func TestLoggerHTTPMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newPanicTestLogger(&buf, nil)
+
+	h := logger.HTTPMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panicky()
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	p := decodePanicPayload(t, &buf)
+	if p.Path != "/orders" || p.PanicType != "string" {
+		t.Fatalf("unexpected payload: %s", buf.String())
+	}
+}
+
+func TestLoggerRecoverKeepsAttrsWithPercentMessage(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newPanicTestLogger(&buf, nil)
+
+	func() {
+		defer logger.Recover(context.Background(), "worker %d crashed at 100%")
+		panicky()
+	}()
+
+	p := decodePanicPayload(t, &buf)
+	if p.Msg != "worker %d crashed at 100%" || p.Panic != "boom" || p.PanicType != "string" || len(p.Stack) == 0 {
+		t.Fatalf("panic attrs must not be consumed as format operands: %s", buf.String())
+	}
+}
+
+func TestLoggerHTTPMiddlewareHeaderAlreadyWritten(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newPanicTestLogger(&buf, nil)
+
+	h := logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("partial"))
+		panicky()
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	if rec.Code != http.StatusAccepted || rec.Body.String() != "partial" {
+		t.Fatalf("response must not be rewritten after headers were sent: %d %q", rec.Code, rec.Body.String())
+	}
+	if p := decodePanicPayload(t, &buf); p.Path != "/stream" {
+		t.Fatalf("panic should still be logged: %s", buf.String())
+	}
+}
+
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed string
+}
+
+func (p *pushRecorder) Push(target string, _ *http.PushOptions) error {
+	p.pushed = target
+	return nil
+}
+
+func TestLoggerHTTPMiddlewareForwardsOptionalInterfaces(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newPanicTestLogger(&buf, nil)
+
+	h := logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if _, ok := w.(http.Flusher); !ok {
+			t.Errorf("middleware writer must implement http.Flusher")
+		}
+		p, ok := w.(http.Pusher)
+		if !ok {
+			t.Errorf("middleware writer must implement http.Pusher")
+			return
+		}
+		if err := p.Push("/app.js", nil); err != nil {
+			t.Errorf("push: %v", err)
+		}
+	}))
+	rec := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.pushed != "/app.js" {
+		t.Fatalf("push should reach the underlying writer, got %q", rec.pushed)
+	}
+}
+
+func TestLoggerHTTPMiddlewareHijack(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newPanicTestLogger(&buf, nil)
+
+	srv := httptest.NewServer(logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			http.Error(w, "no hijacker", http.StatusNotImplemented)
+			return
+		}
+		conn, rw, err := hj.Hijack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+		_ = rw.Flush()
+		// 接管后的 panic 不应再尝试写 500 响应。
+		panicky()
+	})))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_, _ = conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n"))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101 through the middleware, got %d", resp.StatusCode)
+	}
+}
+
+func TestNilLoggerHTTPMiddlewareUsesDefault(t *testing.T) {
+	var buf bytes.Buffer
+	resetForTest()
+	ResetGlobalLogger(&buf, true, false)
+
+	var logger *Logger
+	h := logger.HTTPMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panicky()
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nil", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	if !strings.Contains(buf.String(), "http handler panic") || !strings.Contains(buf.String(), "/nil") {
+		t.Fatalf("nil logger should fall back to Default(): %q", buf.String())
+	}
+}
@@ -0,0 +1,205 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ProcessorFunc 在记录分发到 text/JSON/订阅之前对其进行加工。
+// 可增删改属性、改写消息或调整级别；返回 false 表示丢弃该记录。
+type ProcessorFunc func(ctx context.Context, r *Record) bool
+
+const (
+	processorScopeGlobal = "global"
+	processorScopeLogger = "logger"
+)
+
+// ProcessorDiagnostics 描述处理器的执行统计。
+type ProcessorDiagnostics struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Scope   string `json:"scope"`
+	Calls   uint64 `json:"calls"`
+	Dropped uint64 `json:"dropped"`
+	Panics  uint64 `json:"panics"`
+}
+
+type processorEntry struct {
+	id      string
+	name    string
+	scope   string
+	fn      ProcessorFunc
+	calls   atomic.Uint64
+	dropped atomic.Uint64
+	panics  atomic.Uint64
+}
+
+var (
+	processorMu      sync.Mutex
+	globalProcessors atomic.Pointer[[]*processorEntry]
+	processorNextID  atomic.Int64
+)
+
+func newProcessorEntry(name, scope string, fn ProcessorFunc) *processorEntry {
+	return &processorEntry{
+		id:    fmt.Sprintf("%s-%d", name, processorNextID.Add(1)),
+		name:  name,
+		scope: scope,
+		fn:    fn,
+	}
+}
+
+// AddProcessor 在全局处理器链末尾注册处理器，返回可用于移除的 ID。
+// 全局处理器先于 Logger 级处理器执行。
+func AddProcessor(name string, fn ProcessorFunc) string {
+	if fn == nil {
+		return ""
+	}
+	entry := newProcessorEntry(name, processorScopeGlobal, fn)
+	processorMu.Lock()
+	defer processorMu.Unlock()
+	chain := append(slices.Clone(currentGlobalProcessors()), entry)
+	globalProcessors.Store(&chain)
+	return entry.id
+}
+
+// RemoveProcessor 根据 ID 移除全局处理器。
+func RemoveProcessor(id string) bool {
+	processorMu.Lock()
+	defer processorMu.Unlock()
+	current := currentGlobalProcessors()
+	for i, entry := range current {
+		if entry.id == id {
+			chain := slices.Delete(slices.Clone(current), i, i+1)
+			globalProcessors.Store(&chain)
+			return true
+		}
+	}
+	return false
+}
+
+// ListProcessors 返回全局处理器名称（按执行顺序）。
+func ListProcessors() []string {
+	chain := currentGlobalProcessors()
+	names := make([]string, len(chain))
+	for i, entry := range chain {
+		names[i] = entry.name
+	}
+	return names
+}
+
+// CollectProcessorDiagnostics 返回全局处理器的执行统计。
+func CollectProcessorDiagnostics() []ProcessorDiagnostics {
+	return processorDiagnostics(currentGlobalProcessors())
+}
+
+func currentGlobalProcessors() []*processorEntry {
+	if chain := globalProcessors.Load(); chain != nil {
+		return *chain
+	}
+	return nil
+}
+
+// WithProcessor 返回在 Logger 级处理器链末尾追加 fn 的新 Logger。
+func (l *Logger) WithProcessor(name string, fn ProcessorFunc) *Logger {
+	if l == nil || fn == nil {
+		return l
+	}
+	newLogger := l.clone()
+	newLogger.processors = append(newLogger.processors, newProcessorEntry(name, processorScopeLogger, fn))
+	return newLogger
+}
+
+// ProcessorDiagnostics 返回作用于当前 Logger 的处理器（全局 + 实例）执行统计。
+func (l *Logger) ProcessorDiagnostics() []ProcessorDiagnostics {
+	diags := CollectProcessorDiagnostics()
+	if l != nil {
+		diags = append(diags, processorDiagnostics(l.processors)...)
+	}
+	return diags
+}
+
+func processorDiagnostics(chain []*processorEntry) []ProcessorDiagnostics {
+	diags := make([]ProcessorDiagnostics, 0, len(chain))
+	for _, entry := range chain {
+		diags = append(diags, ProcessorDiagnostics{
+			ID:      entry.id,
+			Name:    entry.name,
+			Scope:   entry.scope,
+			Calls:   entry.calls.Load(),
+			Dropped: entry.dropped.Load(),
+			Panics:  entry.panics.Load(),
+		})
+	}
+	return diags
+}
+
+// hasProcessors 判断是否存在作用于当前 Logger 的全局或实例处理器。
+func (l *Logger) hasProcessors() bool {
+	return len(l.processors) > 0 || len(currentGlobalProcessors()) > 0
+}
+
+// runProcessors 依次执行全局与实例处理器，返回 false 表示记录被丢弃。
+func (l *Logger) runProcessors(ctx context.Context, r *slog.Record) bool {
+	global := currentGlobalProcessors()
+	if len(global) == 0 && len(l.processors) == 0 {
+		return true
+	}
+	for _, chain := range [2][]*processorEntry{global, l.processors} {
+		for _, entry := range chain {
+			if !entry.run(ctx, r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (p *processorEntry) run(ctx context.Context, r *slog.Record) (keep bool) {
+	p.calls.Add(1)
+	defer func() {
+		if rec := recover(); rec != nil {
+			// 处理器 panic 不应影响日志主链路，保留记录继续分发。
+			p.panics.Add(1)
+			ext.emitStageDiagnostics("processor:"+p.name, fmt.Sprintf("panic=%v", rec))
+			keep = true
+		}
+	}()
+
+	before := r.Message
+	beforeLevel := r.Level
+	keep = p.fn(ctx, r)
+	if !keep {
+		p.dropped.Add(1)
+		ext.emitStageDiagnostics("processor:"+p.name, fmt.Sprintf("dropped msg=%q", before))
+		return false
+	}
+	if r.Message != before || r.Level != beforeLevel {
+		ext.emitStageDiagnostics("processor:"+p.name, fmt.Sprintf("msg=%q->%q level=%s->%s", before, r.Message, beforeLevel, r.Level))
+	}
+	return true
+}
+
+// RecordAttrs 返回记录的全部顶层属性副本。
+func RecordAttrs(r Record) []Attr {
+	attrs := make([]Attr, 0, r.NumAttrs())
+	r.Attrs(func(a Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// SetRecordAttrs 以 attrs 替换记录的全部属性，便于处理器删除或重命名属性。
+func SetRecordAttrs(r *Record, attrs ...Attr) {
+	if r == nil {
+		return
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+	*r = nr
+}
@@ -0,0 +1,109 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestProcessorsRewriteDropAndRelevel(t *testing.T) {
+	resetForTest()
+	SetLevelInfo()
+	defer resetForTest()
+
+	var diag bytes.Buffer
+	EnableDiagnosticsLogging(true, &diag)
+	defer EnableDiagnosticsLogging(false)
+
+	dropID := AddProcessor("drop-health", func(_ context.Context, r *Record) bool {
+		return r.Message != "healthz"
+	})
+	defer RemoveProcessor(dropID)
+	renameID := AddProcessor("rename-uid", func(_ context.Context, r *Record) bool {
+		attrs := RecordAttrs(*r)
+		kept := attrs[:0]
+		for _, a := range attrs {
+			switch a.Key {
+			case "uid":
+				a.Key = "user_id"
+			case "password":
+				continue
+			}
+			kept = append(kept, a)
+		}
+		SetRecordAttrs(r, kept...)
+		r.AddAttrs(String("env", "test"))
+		return true
+	})
+	defer RemoveProcessor(renameID)
+
+	if got := ListProcessors(); len(got) != 2 || got[0] != "drop-health" || got[1] != "rename-uid" {
+		t.Fatalf("unexpected processor order: %v", got)
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false).WithProcessor("escalate", func(_ context.Context, r *Record) bool {
+		if strings.HasPrefix(r.Message, "slow") {
+			r.Level = LevelWarn
+			r.Message = "[slow] " + r.Message
+		}
+		return true
+	})
+
+	logger.Info("healthz")
+	logger.Info("login", "uid", 7, "password", "secret")
+	logger.Debug("slow query")
+
+	out := buf.String()
+	if strings.Contains(out, "healthz") {
+		t.Fatalf("expected record to be dropped:\n%s", out)
+	}
+	if !strings.Contains(out, "user_id=7") || strings.Contains(out, "secret") || !strings.Contains(out, "env=test") {
+		t.Fatalf("expected attrs to be rewritten:\n%s", out)
+	}
+	if !strings.Contains(out, "[W] [slow] slow query") {
+		t.Fatalf("expected debug record to be re-leveled to warn:\n%s", out)
+	}
+
+	diags := logger.ProcessorDiagnostics()
+	if len(diags) != 3 || diags[0].Dropped != 1 || diags[2].Scope != processorScopeLogger {
+		t.Fatalf("unexpected processor diagnostics: %+v", diags)
+	}
+	if !strings.Contains(diag.String(), "stage=processor:drop-health dropped") {
+		t.Fatalf("expected processor diagnostics output, got %q", diag.String())
+	}
+}
+
+func TestProcessorsSeeDisabledRecords(t *testing.T) {
+	resetForTest()
+	SetLevelInfo()
+	defer resetForTest()
+
+	calls := 0
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false).WithProcessor("count", func(_ context.Context, r *Record) bool {
+		calls++
+		return true
+	})
+	// 处理器需要看到被过滤的记录才能调整级别；未被调整的记录仍然不会输出。
+	logger.Debug("not emitted")
+	if calls != 1 || buf.Len() != 0 {
+		t.Fatalf("disabled record must reach processors but not outputs: calls=%d out=%q", calls, buf.String())
+	}
+}
+
+func TestProcessorPanicKeepsRecord(t *testing.T) {
+	resetForTest()
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false).WithProcessor("boom", func(context.Context, *Record) bool {
+		panic("processor failure")
+	})
+	logger.Info("survives")
+	if !strings.Contains(buf.String(), "survives") {
+		t.Fatalf("expected record to survive processor panic, got %q", buf.String())
+	}
+	if diags := logger.ProcessorDiagnostics(); diags[len(diags)-1].Panics != 1 {
+		t.Fatalf("expected panic to be counted: %+v", diags)
+	}
+}
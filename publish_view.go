@@ -79,7 +79,7 @@ func (l *Logger) renderSubscription(ctx context.Context, raw slog.Record, publis
 func (l *Logger) renderSubscriptionText(ctx context.Context, raw slog.Record, published slog.Record) string {
 	if l != nil && l.text != nil {
 		return l.renderWithHandlerChain(ctx, raw, l.text.Handler(), func(buf *bytes.Buffer) slog.Handler {
-			return NewConsoleHandler(buf, l.noColor, l.subscriptionHandlerOptions())
+			return NewConsoleHandler(buf, l.noColor, l.subscriptionHandlerOptions(), l.renderConfig.console...)
 		})
 	}
 	return l.renderPublishedText(published)
@@ -88,7 +88,7 @@ func (l *Logger) renderSubscriptionText(ctx context.Context, raw slog.Record, pu
 func (l *Logger) renderSubscriptionJSON(ctx context.Context, raw slog.Record, published slog.Record) string {
 	if l != nil && l.json != nil {
 		return l.renderWithHandlerChain(ctx, raw, l.json.Handler(), func(buf *bytes.Buffer) slog.Handler {
-			return NewJSONHandler(buf, l.subscriptionHandlerOptions())
+			return l.renderConfig.jsonSchema.newJSONHandler(buf, l.subscriptionHandlerOptions(), l.renderConfig.timeLayout, l.renderConfig.timeZone)
 		})
 	}
 	return l.renderPublishedJSON(published)
@@ -96,7 +96,7 @@ func (l *Logger) renderSubscriptionJSON(ctx context.Context, raw slog.Record, pu
 
 func (l *Logger) renderPublishedText(record slog.Record) string {
 	var buf bytes.Buffer
-	handler := NewConsoleHandler(&buf, l.noColor, l.subscriptionHandlerOptions())
+	handler := NewConsoleHandler(&buf, l.noColor, l.subscriptionHandlerOptions(), l.renderConfig.console...)
 	if err := handler.Handle(context.Background(), record); err != nil {
 		return ""
 	}
@@ -137,7 +137,7 @@ func cloneRenderChain(original slog.Handler, leaf slog.Handler) slog.Handler {
 
 func (l *Logger) renderPublishedJSON(record slog.Record) string {
 	var buf bytes.Buffer
-	handler := NewJSONHandler(&buf, l.subscriptionHandlerOptions())
+	handler := l.renderConfig.jsonSchema.newJSONHandler(&buf, l.subscriptionHandlerOptions(), l.renderConfig.timeLayout, l.renderConfig.timeZone)
 	if err := handler.Handle(context.Background(), record); err != nil {
 		return ""
 	}
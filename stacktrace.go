@@ -0,0 +1,147 @@
+package slog
+
+import (
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StacktraceKey 是 Config.StacktraceLevel 采集的调用栈属性名。
+const StacktraceKey = "stacktrace"
+
+const defaultStacktraceDepth = 32
+
+// StackFrame 描述调用栈中的一帧。
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Stacktrace 是从日志调用点开始、已过滤 wrapper 帧的调用栈。
+// JSON 输出为帧数组，控制台输出为缩进的多行文本。
+type Stacktrace []StackFrame
+
+// String 以 "function (file:line)" 形式、分号分隔输出，供单行格式（logfmt 等）使用。
+func (s Stacktrace) String() string {
+	var sb strings.Builder
+	for i, f := range s {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(f.Function)
+		sb.WriteString(" (")
+		sb.WriteString(f.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(f.Line))
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
+
+// SetStacktraceLevel 为不低于 level 的记录附加调用栈。
+func (c *Config) SetStacktraceLevel(level Level) {
+	if c == nil {
+		return
+	}
+	c.StacktraceLevel = level
+}
+
+// stacktraceFor 按配置为 level 采集调用栈，未启用或级别不足时返回 nil。
+func (l *Logger) stacktraceFor(level Level) Stacktrace {
+	if l.config == nil || l.config.StacktraceLevel == nil || level < l.config.StacktraceLevel.Level() {
+		return nil
+	}
+	depth := l.config.StacktraceDepth
+	if depth <= 0 {
+		depth = defaultStacktraceDepth
+	}
+	return captureStacktrace(3, depth)
+}
+
+// captureStacktrace 采集调用栈，跳过 runtime/testing/标准库 slog 帧以及 RegisterCallerSkipPrefix 注册的前缀。
+func captureStacktrace(skip, depth int) Stacktrace {
+	pcs := make([]uintptr, depth+16)
+	n := runtime.Callers(skip, pcs)
+	if n == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make(Stacktrace, 0, min(n, depth))
+	for len(stack) < depth {
+		frame, more := frames.Next()
+		if !shouldSkipCallerFrame(frame) {
+			stack = append(stack, StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack
+}
+
+// splitStacktrace 从属性（含分组）中取出调用栈，返回剔除调用栈后的属性。
+func splitStacktrace(a slog.Attr) (slog.Attr, Stacktrace, bool) {
+	switch a.Value.Kind() {
+	case slog.KindAny:
+		if st, ok := a.Value.Any().(Stacktrace); ok {
+			return slog.Attr{}, st, true
+		}
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		for i, child := range attrs {
+			rest, st, ok := splitStacktrace(child)
+			if !ok {
+				continue
+			}
+			kept := make([]slog.Attr, 0, len(attrs))
+			kept = append(kept, attrs[:i]...)
+			if rest.Key != "" {
+				kept = append(kept, rest)
+			}
+			kept = append(kept, attrs[i+1:]...)
+			return slog.Attr{Key: a.Key, Value: slog.GroupValue(kept...)}, st, true
+		}
+	}
+	return a, nil, false
+}
+
+// appendStacktrace 以缩进行输出调用栈，文件路径保留最后两级目录。
+func (h *handler) appendStacktrace(sb *buffer, stack Stacktrace) {
+	for _, f := range stack {
+		colored := !h.noColor && h.styles().Stack != ""
+		sb.AppendStringIf(colored, h.styles().Stack)
+		sb.AppendString("    at ")
+		sb.AppendString(f.Function)
+		sb.AppendString(" (")
+		sb.AppendString(shortStackFile(f.File))
+		sb.AppendByte(':')
+		sb.AppendString(strconv.Itoa(f.Line))
+		sb.AppendByte(')')
+		sb.AppendStringIf(colored, ansiReset)
+		sb.AppendByte('\n')
+	}
+}
+
+func shortStackFile(file string) string {
+	dir, base := filepath.Split(file)
+	if dir == "" {
+		return base
+	}
+	return filepath.Join(filepath.Base(dir), base)
+}
+
+// recordHasStacktrace 判断记录是否已携带调用栈（如 Recover 采集的 panic 栈）。
+func recordHasStacktrace(r slog.Record) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		_, found = a.Value.Any().(Stacktrace)
+		return !found
+	})
+	return found
+}
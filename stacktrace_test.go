@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStacktraceLevelConsole(t *testing.T) {
+	resetForTest()
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.NoColor = true
+	cfg.SetEnableText(true)
+	cfg.SetEnableJSON(false)
+	cfg.SetStacktraceLevel(LevelError)
+	logger := NewLoggerWithConfig(&buf, cfg)
+
+	logger.Warn("no stack")
+	logger.WithGroup("req").Error("with stack", "id", 7)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected stack lines, got:\n%s", buf.String())
+	}
+	if strings.Contains(lines[0], "at ") || !strings.Contains(lines[1], "with stack") || !strings.Contains(lines[1], "req.id=7") {
+		t.Fatalf("unexpected record lines:\n%s", buf.String())
+	}
+	if strings.Contains(lines[1], StacktraceKey+"=") {
+		t.Fatalf("stack must not be rendered inline:\n%s", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "    at github.com/darkit/slog.TestStacktraceLevelConsole (") ||
+		!strings.Contains(lines[2], "stacktrace_test.go:") {
+		t.Fatalf("expected first frame to be the caller, got %q", lines[2])
+	}
+	for _, line := range lines[2:] {
+		if strings.Contains(line, "(*Logger)") || strings.Contains(line, "runtime.") {
+			t.Fatalf("wrapper/runtime frames must be filtered: %q", line)
+		}
+	}
+}
+
+func TestStacktraceLevelJSON(t *testing.T) {
+	resetForTest()
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.SetEnableText(false)
+	cfg.SetEnableJSON(true)
+	cfg.SetStacktraceLevel(LevelWarn)
+	cfg.StacktraceDepth = 1
+	logger := NewLoggerWithConfig(&buf, cfg)
+
+	logger.Warn("json stack")
+
+	var payload struct {
+		Stack []StackFrame `json:"stacktrace"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if len(payload.Stack) != 1 || payload.Stack[0].Function != "github.com/darkit/slog.TestStacktraceLevelJSON" || payload.Stack[0].Line == 0 {
+		t.Fatalf("unexpected frames: %+v", payload.Stack)
+	}
+}
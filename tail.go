@@ -0,0 +1,553 @@
+package slog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultTailBufferSize   = 256
+	defaultTailWriteTimeout = 3 * time.Second
+)
+
+var (
+	errTailServerClosed = errors.New("slog: tail server closed")
+	errTailRemotePeer   = errors.New("slog: tail server only accepts loopback clients without TLSConfig or Authorize")
+)
+
+// TailServerOptions 控制实时日志流服务。
+type TailServerOptions struct {
+	// Network 监听网络类型，支持 tcp、tcp4、tcp6 与 unix；为空时默认 tcp。
+	Network string
+	// Addr 监听地址；unix 网络下为 socket 文件路径。
+	Addr string
+	// Subscribe 每个连接使用的订阅选项；BufferSize 为 0 时默认 256。
+	Subscribe SubscribeOptions
+	// WriteTimeout 单条事件写入超时，超时视为客户端失联；<=0 时默认 3s。
+	WriteTimeout time.Duration
+	// TLSConfig 非空时以 TLS 提供服务，可通过 ClientAuth 要求客户端证书。
+	TLSConfig *tls.Config
+	// Authorize 在订阅前校验连接，返回错误即断开；TLS 连接已完成握手，可读取 ConnectionState。
+	// 为 nil 且未配置 TLSConfig 时，tcp 连接仅允许来自回环地址，unix socket 不受限制。
+	Authorize func(conn net.Conn) error
+}
+
+// TailServer 通过 TCP/unix socket 以 NDJSON 形式推送订阅事件，便于直接 tail 线上实例。
+// 每个连接拥有独立订阅，慢客户端只会按背压策略丢弃自己的事件，不会阻塞主链路。
+type TailServer struct {
+	opts TailServerOptions
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]context.CancelFunc
+	wg       sync.WaitGroup
+	closed   atomic.Bool
+	served   atomic.Uint64
+}
+
+// TailEvent 是 tail 协议中单行 NDJSON 的结构。
+type TailEvent struct {
+	Time     time.Time `json:"time"`
+	Level    Level     `json:"level"`
+	Message  string    `json:"msg"`
+	Source   *Source   `json:"source,omitempty"`
+	Attrs    TailAttrs `json:"attrs,omitempty"`
+	Rendered string    `json:"rendered,omitempty"`
+	Format   string    `json:"format,omitempty"`
+}
+
+// NewTailServer 创建实时日志流服务，需调用 ListenAndServe 或 Serve 启动。
+func NewTailServer(opts TailServerOptions) *TailServer {
+	if opts.Network == "" {
+		opts.Network = "tcp"
+	}
+	if opts.Subscribe.BufferSize == 0 {
+		opts.Subscribe.BufferSize = defaultTailBufferSize
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = defaultTailWriteTimeout
+	}
+	return &TailServer{
+		opts:  opts,
+		conns: make(map[net.Conn]context.CancelFunc),
+	}
+}
+
+// ListenAndServe 监听配置的地址并阻塞处理连接，直到 Close 被调用。
+func (s *TailServer) ListenAndServe() error {
+	if s.opts.Addr == "" {
+		return NewInvalidInputError("addr", "non-empty listen address", "empty")
+	}
+	if s.opts.Network == "unix" {
+		// 清理上次异常退出遗留的 socket 文件，避免 address already in use。
+		if info, err := os.Stat(s.opts.Addr); err == nil && info.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(s.opts.Addr)
+		}
+	}
+	ln, err := net.Listen(s.opts.Network, s.opts.Addr)
+	if err != nil {
+		return NewInitializationError("tail", "listen", err)
+	}
+	return s.Serve(ln)
+}
+
+// Serve 在给定 listener 上接受连接并推送日志事件，直到 Close 被调用。
+func (s *TailServer) Serve(ln net.Listener) error {
+	if ln == nil {
+		return NewInvalidInputError("listener", "non-nil net.Listener", "nil")
+	}
+	s.mu.Lock()
+	if s.closed.Load() {
+		s.mu.Unlock()
+		_ = ln.Close()
+		return errTailServerClosed
+	}
+	if s.opts.TLSConfig != nil {
+		ln = tls.NewListener(ln, s.opts.TLSConfig)
+	}
+	s.listener = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if s.closed.Load() {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+		// 持锁登记协程，避免与 Close 的 wg.Wait 竞争；已关闭时直接断开新连接。
+		s.mu.Lock()
+		if s.closed.Load() {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+// Addr 返回实际监听地址，未启动时返回 nil。
+func (s *TailServer) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Connections 返回当前在线的 tail 客户端数量。
+func (s *TailServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Served 返回已推送给客户端的事件总数。
+func (s *TailServer) Served() uint64 {
+	return s.served.Load()
+}
+
+// Close 停止监听并断开所有客户端，等待连接协程退出。
+func (s *TailServer) Close() error {
+	if !s.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	s.mu.Lock()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn, cancel := range s.conns {
+		cancel()
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *TailServer) trackConn(conn net.Conn, cancel context.CancelFunc) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed.Load() {
+		return false
+	}
+	s.conns[conn] = cancel
+	return true
+}
+
+func (s *TailServer) untrackConn(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+}
+
+// authorize 完成 TLS 握手并执行 Authorize 钩子，未配置时退回回环地址限制。
+func (s *TailServer) authorize(conn net.Conn) error {
+	if tc, ok := conn.(*tls.Conn); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), s.opts.WriteTimeout)
+		err := tc.HandshakeContext(ctx)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	if s.opts.Authorize != nil {
+		return s.opts.Authorize(conn)
+	}
+	if s.opts.TLSConfig != nil {
+		return nil
+	}
+	return tailLoopbackOnly(conn)
+}
+
+// tailLoopbackOnly 拒绝来自非回环地址的 tcp 连接。
+func tailLoopbackOnly(conn net.Conn) error {
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok || addr.IP.IsLoopback() {
+		return nil
+	}
+	return errTailRemotePeer
+}
+
+func (s *TailServer) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+
+	if err := s.authorize(conn); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	events, cancel := SubscribeWithOptions(s.opts.Subscribe)
+	defer cancel()
+	if !s.trackConn(conn, cancel) {
+		_ = conn.Close()
+		return
+	}
+	defer func() {
+		s.untrackConn(conn)
+		_ = conn.Close()
+	}()
+
+	// 客户端只读不写；读到 EOF 即表示对端断开，此时取消订阅以结束推送循环。
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		cancel()
+	}()
+
+	w := bufio.NewWriter(conn)
+	for event := range events {
+		line, err := json.Marshal(NewTailEvent(event))
+		if err != nil {
+			continue
+		}
+		_ = conn.SetWriteDeadline(time.Now().Add(s.opts.WriteTimeout))
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return
+		}
+		// 队列中没有积压时立即刷出，积压时批量写以减少系统调用。
+		if len(events) == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+		s.served.Add(1)
+	}
+	// 订阅结束时刷出缓冲中尚未发送的事件。
+	_ = w.Flush()
+}
+
+// NewTailEvent 将订阅事件转换为可序列化的 tail 事件。
+func NewTailEvent(event SubscriptionEvent) TailEvent {
+	r := event.Record
+	te := TailEvent{
+		Time:     r.Time,
+		Level:    r.Level,
+		Message:  r.Message,
+		Rendered: event.Rendered,
+		Format:   event.Format,
+	}
+	if r.PC != 0 {
+		f := frame(r.PC)
+		te.Source = &Source{Function: f.Function, File: f.File, Line: f.Line}
+	}
+	if r.NumAttrs() > 0 {
+		te.Attrs = make(TailAttrs, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			te.Attrs = append(te.Attrs, a)
+			return true
+		})
+	}
+	return te
+}
+
+// Record 将 tail 事件还原为 Record，便于交给任意 Handler 重新渲染。
+// 远端 PC 无法还原，调用源会以 source 属性的形式附加。
+func (e TailEvent) Record() Record {
+	r := slog.NewRecord(e.Time, e.Level, e.Message, 0)
+	if e.Source != nil && e.Source.File != "" {
+		r.AddAttrs(slog.String(SourceKey, filepath.Base(e.Source.File)+":"+strconv.Itoa(e.Source.Line)))
+	}
+	r.AddAttrs(e.Attrs...)
+	return r
+}
+
+// ReadTailEvents 逐行解码 NDJSON tail 流，直到读取结束或 fn 返回错误。
+func ReadTailEvents(r io.Reader, fn func(TailEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var event TailEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return NewProcessingError("tail", "decode", err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// TailFilter 描述客户端侧过滤条件，零值匹配所有事件。
+type TailFilter struct {
+	// MinLevel 最低级别；为 nil 时不限制。
+	MinLevel *Level
+	// Contains 消息需包含的子串（大小写敏感）。
+	Contains string
+	// Pattern 消息需匹配的正则。
+	Pattern *regexp.Regexp
+	// Attrs 需要精确匹配的属性，键使用点号连接分组，例如 request.user。
+	Attrs map[string]string
+}
+
+// Match 判断事件是否满足全部过滤条件。
+func (f TailFilter) Match(e TailEvent) bool {
+	if f.MinLevel != nil && e.Level < *f.MinLevel {
+		return false
+	}
+	if f.Contains != "" && !strings.Contains(e.Message, f.Contains) {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(e.Message) {
+		return false
+	}
+	if len(f.Attrs) == 0 {
+		return true
+	}
+	flat := make(map[string]string, len(e.Attrs))
+	flattenAttrValues(flat, "", e.Attrs)
+	for key, want := range f.Attrs {
+		if got, ok := flat[key]; !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// flattenAttrValues 以点号连接分组键，将属性展开为字符串值映射。
+func flattenAttrValues(dst map[string]string, prefix string, attrs []slog.Attr) {
+	for _, a := range attrs {
+		key := a.Key
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		v := a.Value.Resolve()
+		if v.Kind() == slog.KindGroup {
+			flattenAttrValues(dst, key, v.Group())
+			continue
+		}
+		dst[key] = v.String()
+	}
+}
+
+// TailAttrs 以保持顺序的 JSON 对象编码属性列表，分组编码为嵌套对象。
+// 解码时整数还原为 int64，小数为 float64，数组为 []any。
+type TailAttrs []slog.Attr
+
+// MarshalJSON 按原始顺序输出属性对象。
+func (a TailAttrs) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeTailAttrs(&buf, a); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON 按 JSON 中出现的顺序还原属性。
+func (a *TailAttrs) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		*a = nil
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("slog: tail attrs must be an object, got %v", tok)
+	}
+	attrs, err := readTailObject(dec)
+	if err != nil {
+		return err
+	}
+	*a = attrs
+	return nil
+}
+
+func writeTailAttrs(buf *bytes.Buffer, attrs []slog.Attr) error {
+	buf.WriteByte('{')
+	first := true
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+		if attr.Equal(slog.Attr{}) {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		key, err := json.Marshal(attr.Key)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		if attr.Value.Kind() == slog.KindGroup {
+			if err := writeTailAttrs(buf, attr.Value.Group()); err != nil {
+				return err
+			}
+			continue
+		}
+		val, err := json.Marshal(tailJSONValue(attr.Value))
+		if err != nil {
+			val, _ = json.Marshal(attr.Value.String())
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func tailJSONValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case error:
+			return x.Error()
+		case json.Marshaler:
+			return x
+		case fmt.Stringer:
+			return x.String()
+		}
+		return v.Any()
+	default:
+		return v.Any()
+	}
+}
+
+func readTailObject(dec *json.Decoder) ([]slog.Attr, error) {
+	var attrs []slog.Attr
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("slog: unexpected tail attr key %v", tok)
+		}
+		value, err := readTailValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, slog.Attr{Key: key, Value: value})
+	}
+	// 消费结束的 '}'
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+func readTailValue(dec *json.Decoder) (slog.Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return slog.Value{}, err
+	}
+	switch x := tok.(type) {
+	case json.Delim:
+		switch x {
+		case '{':
+			attrs, err := readTailObject(dec)
+			if err != nil {
+				return slog.Value{}, err
+			}
+			return slog.GroupValue(attrs...), nil
+		case '[':
+			var items []any
+			for dec.More() {
+				var item any
+				if err := dec.Decode(&item); err != nil {
+					return slog.Value{}, err
+				}
+				items = append(items, item)
+			}
+			if _, err := dec.Token(); err != nil {
+				return slog.Value{}, err
+			}
+			return slog.AnyValue(items), nil
+		}
+		return slog.Value{}, fmt.Errorf("slog: unexpected tail delimiter %v", x)
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return slog.Int64Value(i), nil
+		}
+		f, err := x.Float64()
+		if err != nil {
+			return slog.StringValue(x.String()), nil
+		}
+		return slog.Float64Value(f), nil
+	case string:
+		return slog.StringValue(x), nil
+	case bool:
+		return slog.BoolValue(x), nil
+	case nil:
+		return slog.AnyValue(nil), nil
+	default:
+		return slog.AnyValue(x), nil
+	}
+}
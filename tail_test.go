@@ -0,0 +1,303 @@
+package slog
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailServerStreamsNDJSON(t *testing.T) {
+	resetForTest()
+	var sink bytes.Buffer
+	logger := NewLogger(&sink, true, false)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := NewTailServer(TailServerOptions{})
+	go func() { _ = server.Serve(ln) }()
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for server.Connections() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("tail connection not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	logger.WithGroup("req").Warn("tail me", "user", "alice", "count", 3)
+
+	events := make(chan TailEvent, 1)
+	go func() {
+		_ = ReadTailEvents(conn, func(event TailEvent) error {
+			events <- event
+			return errStopTail
+		})
+	}()
+
+	select {
+	case event := <-events:
+		if event.Message != "tail me" || event.Level != LevelWarn {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+		if len(event.Attrs) != 1 || event.Attrs[0].Key != "req" {
+			t.Fatalf("expected grouped attrs, got %v", event.Attrs)
+		}
+		group := event.Attrs[0].Value.Group()
+		if len(group) != 2 || group[0].Key != "user" || group[1].Key != "count" {
+			t.Fatalf("expected attr order to be preserved, got %v", group)
+		}
+		if group[1].Value.Kind() != KindInt64 || group[1].Value.Int64() != 3 {
+			t.Fatalf("expected integer attr to round-trip, got %v", group[1].Value)
+		}
+		if event.Format != "text" || !strings.Contains(event.Rendered, "tail me") {
+			t.Fatalf("expected rendered text, got format=%q rendered=%q", event.Format, event.Rendered)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for tail event")
+	}
+}
+
+func TestTailServerCloseDisconnectsClients(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := NewTailServer(TailServerOptions{})
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ln) }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("serve returned error after close: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serve did not return after close")
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("expected client connection to be closed")
+	}
+}
+
+func TestTailFilterMatch(t *testing.T) {
+	var event TailEvent
+	line := `{"time":"2026-01-02T03:04:05Z","level":"ERROR","msg":"db timeout","attrs":{"req":{"user":"bob"},"code":500}}`
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	warn := LevelWarn
+	fatal := LevelFatal
+	cases := []struct {
+		name   string
+		filter TailFilter
+		want   bool
+	}{
+		{"zero", TailFilter{}, true},
+		{"level pass", TailFilter{MinLevel: &warn}, true},
+		{"level reject", TailFilter{MinLevel: &fatal}, false},
+		{"contains", TailFilter{Contains: "timeout"}, true},
+		{"pattern reject", TailFilter{Pattern: regexp.MustCompile(`^cache`)}, false},
+		{"nested attr", TailFilter{Attrs: map[string]string{"req.user": "bob", "code": "500"}}, true},
+		{"attr mismatch", TailFilter{Attrs: map[string]string{"req.user": "alice"}}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.filter.Match(event); got != tc.want {
+			t.Errorf("%s: Match()=%v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+var errStopTail = errors.New("stop tail")
+
+func TestTailServerTLSAndAuthorize(t *testing.T) {
+	resetForTest()
+	logger := NewLogger(&bytes.Buffer{}, true, false)
+
+	cert, pool := newTailTestCert(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	authorized := make(chan bool, 2)
+	server := NewTailServer(TailServerOptions{
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		Authorize: func(conn net.Conn) error {
+			state := conn.(*tls.Conn).ConnectionState()
+			ok := state.HandshakeComplete && state.ServerName == "tail.test"
+			authorized <- ok
+			if !ok {
+				return errors.New("denied")
+			}
+			return nil
+		},
+	})
+	go func() { _ = server.Serve(ln) }()
+	defer server.Close()
+
+	// 握手完成后才调用 Authorize，拒绝的连接立即断开且不注册订阅。
+	denied, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{RootCAs: pool, ServerName: "localhost"})
+	if err == nil {
+		_ = denied.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err := denied.Read(make([]byte, 1)); err == nil {
+			t.Fatal("expected unauthorized connection to be closed")
+		}
+		denied.Close()
+	}
+	if ok := <-authorized; ok {
+		t.Fatal("expected authorize hook to reject server name")
+	}
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{RootCAs: pool, ServerName: "tail.test"})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	if ok := <-authorized; !ok {
+		t.Fatal("expected authorize hook to accept")
+	}
+	deadline := time.Now().Add(time.Second)
+	for server.Connections() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("tail connection not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	logger.Info("over tls")
+	events := make(chan TailEvent, 1)
+	go func() {
+		_ = ReadTailEvents(conn, func(event TailEvent) error {
+			events <- event
+			return errStopTail
+		})
+	}()
+	select {
+	case event := <-events:
+		if event.Message != "over tls" {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for tail event")
+	}
+}
+
+func TestTailLoopbackOnly(t *testing.T) {
+	cases := map[string]bool{"127.0.0.1": true, "::1": true, "10.0.0.8": false}
+	for ip, want := range cases {
+		conn := tailAddrConn{remote: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000}}
+		if got := tailLoopbackOnly(conn) == nil; got != want {
+			t.Fatalf("tailLoopbackOnly(%s) = %v, want %v", ip, got, want)
+		}
+	}
+	if err := tailLoopbackOnly(tailAddrConn{remote: &net.UnixAddr{Name: "@", Net: "unix"}}); err != nil {
+		t.Fatalf("unix peers must be accepted: %v", err)
+	}
+}
+
+// tailAddrConn 仅用于伪造远端地址。
+type tailAddrConn struct {
+	net.Conn
+	remote net.Addr
+}
+
+func (c tailAddrConn) RemoteAddr() net.Addr { return c.remote }
+
+// newTailTestCert 生成 tail.test 的自签名证书及信任它的证书池。
+func newTailTestCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tail.test"},
+		DNSNames:              []string{"tail.test", "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create cert: %v", err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func TestTailServerCloseRacesWithAccept(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		server := NewTailServer(TailServerOptions{})
+		done := make(chan error, 1)
+		go func() { done <- server.Serve(ln) }()
+
+		stop := make(chan struct{})
+		dialed := make(chan struct{})
+		go func() {
+			defer close(dialed)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if conn, err := net.Dial("tcp", ln.Addr().String()); err == nil {
+					_ = conn.Close()
+				}
+			}
+		}()
+		time.Sleep(time.Millisecond)
+		if err := server.Close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+		close(stop)
+		<-dialed
+		if err := <-done; err != nil {
+			t.Fatalf("serve: %v", err)
+		}
+		if n := server.Connections(); n != 0 {
+			t.Fatalf("expected no tracked connections after close, got %d", n)
+		}
+	}
+}
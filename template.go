@@ -0,0 +1,231 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MessageTemplateKey 是消息模板日志保留原始模板的属性名，便于后端按事件类型聚合。
+const MessageTemplateKey = "msg_template"
+
+// templateCacheKey 区分模板解析结果与 formatLog 的检测结果，二者共用 formatCache。
+type templateCacheKey string
+
+// messageTemplate 是解析后的消息模板。
+type messageTemplate struct {
+	raw   string
+	parts []templatePart
+}
+
+// templatePart 为字面文本或命名占位符（hole 为 true）。
+type templatePart struct {
+	text string
+	name string
+	hint string
+	hole bool
+}
+
+// parseMessageTemplate 解析 "user {user} took {elapsed:ms}" 形式的模板，结果缓存在 formatCache 中。
+// "{{" 与 "}}" 转义为字面花括号，无法识别的占位符按原文保留。
+func parseMessageTemplate(tpl string) *messageTemplate {
+	key := templateCacheKey(tpl)
+	if cached, ok := formatCache.Get(key); ok {
+		if t, ok := cached.(*messageTemplate); ok {
+			return t
+		}
+	}
+
+	t := &messageTemplate{raw: tpl}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			t.parts = append(t.parts, templatePart{text: text.String()})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(tpl); i++ {
+		c := tpl[i]
+		switch {
+		case c == '{' && i+1 < len(tpl) && tpl[i+1] == '{':
+			text.WriteByte('{')
+			i++
+		case c == '}' && i+1 < len(tpl) && tpl[i+1] == '}':
+			text.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(tpl[i+1:], '}')
+			if end < 0 {
+				text.WriteString(tpl[i:])
+				i = len(tpl)
+				continue
+			}
+			body := tpl[i+1 : i+1+end]
+			name, hint, _ := strings.Cut(body, ":")
+			if !isTemplateName(name) {
+				text.WriteString(tpl[i : i+2+end])
+			} else {
+				flush()
+				t.parts = append(t.parts, templatePart{name: name, hint: hint, hole: true})
+			}
+			i += end + 1
+		default:
+			text.WriteByte(c)
+		}
+	}
+	flush()
+
+	formatCache.Put(key, t)
+	return t
+}
+
+func isTemplateName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && r != '.' && r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// render 按位置绑定参数：同名占位符复用首次绑定的值，多余参数按键值对追加为属性，缺少参数的占位符原样保留。
+func (t *messageTemplate) render(args []any) (string, []any) {
+	var sb strings.Builder
+	attrs := make([]any, 0, len(t.parts)+1)
+	bound := make(map[string]any, len(t.parts))
+	next := 0
+	for _, p := range t.parts {
+		if !p.hole {
+			sb.WriteString(p.text)
+			continue
+		}
+		v, ok := bound[p.name]
+		if !ok {
+			if next >= len(args) {
+				sb.WriteByte('{')
+				sb.WriteString(p.name)
+				if p.hint != "" {
+					sb.WriteByte(':')
+					sb.WriteString(p.hint)
+				}
+				sb.WriteByte('}')
+				continue
+			}
+			v = args[next]
+			next++
+			bound[p.name] = v
+			attrs = append(attrs, slog.Any(p.name, v))
+		}
+		sb.WriteString(formatTemplateValue(v, p.hint))
+	}
+	attrs = append(attrs, args[next:]...)
+	attrs = append(attrs, slog.String(MessageTemplateKey, t.raw))
+	return sb.String(), attrs
+}
+
+var templateDurationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// formatTemplateValue 按格式提示渲染值：时长支持 ns/us/ms/s/m/h 单位，其余提示视为 fmt 动词（如 ".2f"、"x"、"q"）。
+func formatTemplateValue(v any, hint string) string {
+	if hint == "" {
+		return fmt.Sprint(v)
+	}
+	if unit, ok := templateDurationUnits[hint]; ok {
+		if d, ok := v.(time.Duration); ok {
+			n := math.Round(float64(d)/float64(unit)*1000) / 1000
+			return strconv.FormatFloat(n, 'f', -1, 64) + hint
+		}
+	}
+	if verb := hint[len(hint)-1]; verb < 128 && formatVerbTable[verb] {
+		return fmt.Sprintf("%"+hint, v)
+	}
+	return fmt.Sprint(v)
+}
+
+// logTemplate 渲染消息模板并记录，占位符的值同时作为结构化属性输出；记录不会输出时跳过渲染。
+func (l *Logger) logTemplate(level Level, ctx context.Context, tpl string, args []any) {
+	if !l.mayEmit(ctx, level) {
+		return
+	}
+	msg, attrs := parseMessageTemplate(tpl).render(args)
+	l.emitRecord(level, ctx, msg, recordVerbatim, attrs)
+}
+
+// LogT 以指定级别记录消息模板日志。
+func (l *Logger) LogT(ctx context.Context, level Level, template string, args ...any) {
+	l.logTemplate(level, ctx, template, args)
+}
+
+// TraceT 记录跟踪级别的消息模板日志。
+func (l *Logger) TraceT(template string, args ...any) {
+	l.logTemplate(LevelTrace, l.ctx, template, args)
+}
+
+// DebugT 记录调试级别的消息模板日志。
+func (l *Logger) DebugT(template string, args ...any) {
+	l.logTemplate(LevelDebug, l.ctx, template, args)
+}
+
+// InfoT 记录信息级别的消息模板日志：
+//
+//	logger.InfoT("user {user} bought {count} items", "alice", 3)
+//	// msg="user alice bought 3 items" user=alice count=3 msg_template="user {user} bought {count} items"
+func (l *Logger) InfoT(template string, args ...any) {
+	l.logTemplate(LevelInfo, l.ctx, template, args)
+}
+
+// WarnT 记录警告级别的消息模板日志。
+func (l *Logger) WarnT(template string, args ...any) {
+	l.logTemplate(LevelWarn, l.ctx, template, args)
+}
+
+// ErrorT 记录错误级别的消息模板日志。
+func (l *Logger) ErrorT(template string, args ...any) {
+	l.logTemplate(LevelError, l.ctx, template, args)
+}
+
+// TraceT 记录全局跟踪级别的消息模板日志。
+func TraceT(template string, args ...any) {
+	l := globalManager.GetDefault()
+	l.logTemplate(LevelTrace, l.ctx, template, args)
+}
+
+// DebugT 记录全局调试级别的消息模板日志。
+func DebugT(template string, args ...any) {
+	l := globalManager.GetDefault()
+	l.logTemplate(LevelDebug, l.ctx, template, args)
+}
+
+// InfoT 记录全局信息级别的消息模板日志。
+func InfoT(template string, args ...any) {
+	l := globalManager.GetDefault()
+	l.logTemplate(LevelInfo, l.ctx, template, args)
+}
+
+// WarnT 记录全局警告级别的消息模板日志。
+func WarnT(template string, args ...any) {
+	l := globalManager.GetDefault()
+	l.logTemplate(LevelWarn, l.ctx, template, args)
+}
+
+// ErrorT 记录全局错误级别的消息模板日志。
+func ErrorT(template string, args ...any) {
+	l := globalManager.GetDefault()
+	l.logTemplate(LevelError, l.ctx, template, args)
+}
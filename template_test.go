@@ -0,0 +1,76 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageTemplateRender(t *testing.T) {
+	tpl := parseMessageTemplate("{{literal}} {user} took {elapsed:ms}, ratio {ratio:.2f}, {user} again, {missing} {bad name}")
+	if parseMessageTemplate(tpl.raw) != tpl {
+		t.Fatalf("template must be cached in formatCache")
+	}
+
+	msg, attrs := tpl.render([]any{"alice", 1203456 * time.Microsecond, 0.5})
+	want := "{literal} alice took 1203.456ms, ratio 0.50, alice again, {missing} {bad name}"
+	if msg != want {
+		t.Fatalf("got %q, want %q", msg, want)
+	}
+	if len(attrs) != 4 || attrs[0].(Attr).Key != "user" || attrs[1].(Attr).Value.Duration() != 1203456*time.Microsecond ||
+		attrs[3].(Attr).Value.String() != tpl.raw {
+		t.Fatalf("unexpected attrs: %v", attrs)
+	}
+}
+
+func TestLoggerInfoT(t *testing.T) {
+	resetForTest()
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.SetEnableText(false)
+	cfg.SetEnableJSON(true)
+	logger := NewLoggerWithConfig(&buf, cfg)
+
+	logger.InfoT("user {user} bought {count} items at 100%", "alice", 3, "order", "A-1")
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if payload["msg"] != "user alice bought 3 items at 100%" || payload["user"] != "alice" ||
+		payload["count"] != float64(3) || payload["order"] != "A-1" ||
+		payload[MessageTemplateKey] != "user {user} bought {count} items at 100%" {
+		t.Fatalf("unexpected payload: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "%!") {
+		t.Fatalf("rendered message must not go through Sprintf: %s", buf.String())
+	}
+}
+
+// countingStringer 统计 String 被调用的次数，用于确认模板是否被渲染。
+type countingStringer struct{ calls *int }
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "value"
+}
+
+func TestLoggerTemplateSkipsDisabledLevels(t *testing.T) {
+	resetForTest()
+	SetLevelInfo()
+	defer resetForTest()
+
+	calls := 0
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, true, false)
+	logger.DebugT("value {v}", countingStringer{&calls})
+	if calls != 0 || buf.Len() != 0 {
+		t.Fatalf("disabled template must not be rendered: calls=%d out=%q", calls, buf.String())
+	}
+	logger.InfoT("value {v}", countingStringer{&calls})
+	if calls == 0 || !strings.Contains(buf.String(), "value value") {
+		t.Fatalf("enabled template must be rendered: calls=%d out=%q", calls, buf.String())
+	}
+}
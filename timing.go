@@ -0,0 +1,117 @@
+package slog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 操作作用域写入上下文字段的键，作用域内的所有日志都会携带这些字段。
+const (
+	OpIDKey       = "op_id"
+	OpParentIDKey = "op_parent_id"
+	OpPathKey     = "op_path"
+)
+
+// 操作结束日志中的结果状态。
+const (
+	OpStatusOK       = "ok"
+	OpStatusError    = "error"
+	OpStatusCanceled = "canceled"
+)
+
+var opIDPrefix = func() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano()&0xffffffff, 16)
+	}
+	return hex.EncodeToString(b[:])
+}()
+
+var opIDSeq atomic.Uint64
+
+// newOpID 生成进程内唯一的操作 ID：随机前缀 + 自增序号。
+func newOpID() string {
+	return opIDPrefix + "-" + strconv.FormatUint(opIDSeq.Add(1), 36)
+}
+
+// Time 开始一个计时操作，以 Debug 级别记录开始，调用返回的 done(err) 记录结束：
+//
+//	done := logger.Time(ctx, "db.query", "table", "orders")
+//	defer func() { done(err) }()
+//
+// 结束日志附带 duration 与 status，成功为 Info，context 取消或超时为 Warn，其余错误为 Error。
+// 需要在子操作中嵌套作用域时使用 TimeScope。
+func (l *Logger) Time(ctx context.Context, op string, args ...any) func(err error) {
+	_, done := l.TimeScope(ctx, op, args...)
+	return done
+}
+
+// TimeScope 与 Time 相同，同时返回携带作用域的上下文：op_id、op_parent_id 与 op_path
+// 写入上下文字段，使用该上下文记录的日志与子操作都会继承它们。
+func (l *Logger) TimeScope(ctx context.Context, op string, args ...any) (context.Context, func(err error)) {
+	if ctx == nil {
+		ctx = l.ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	parent := getFields(ctx)
+	var parentID, path string
+	if parent != nil {
+		parent.mu.RLock()
+		parentID, _ = parent.values[OpIDKey].(string)
+		path, _ = parent.values[OpPathKey].(string)
+		parent.mu.RUnlock()
+	}
+	if path != "" {
+		path += "/" + op
+	} else {
+		path = op
+	}
+
+	fields := parent.clone()
+	fields.mu.Lock()
+	fields.values[OpIDKey] = newOpID()
+	fields.values[OpPathKey] = path
+	if parentID != "" {
+		fields.values[OpParentIDKey] = parentID
+	} else {
+		delete(fields.values, OpParentIDKey)
+	}
+	fields.mu.Unlock()
+	scopeCtx := context.WithValue(ctx, fieldsKey, fields)
+
+	start := time.Now()
+	l.emitRecord(LevelDebug, scopeCtx, op+" started", recordVerbatim, args)
+
+	var once sync.Once
+	return scopeCtx, func(err error) {
+		once.Do(func() {
+			level, status := LevelInfo, OpStatusOK
+			switch {
+			case err == nil:
+			case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+				level, status = LevelWarn, OpStatusCanceled
+			default:
+				level, status = LevelError, OpStatusError
+			}
+			attrs := append(slices.Clip(args),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("status", status),
+			)
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			}
+			l.emitRecord(level, scopeCtx, op+" finished", recordVerbatim, attrs)
+		})
+	}
+}
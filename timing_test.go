@@ -0,0 +1,54 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoggerTimeScopeNesting(t *testing.T) {
+	resetForTest()
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.SetEnableText(false)
+	cfg.SetEnableJSON(true)
+	logger := NewLoggerWithConfig(&buf, cfg)
+	logger.SetLevel(LevelDebug)
+
+	ctx, doneReq := logger.TimeScope(context.Background(), "http.request", "path", "/orders")
+	doneQuery := logger.Time(ctx, "db.query")
+	doneQuery(errors.New("timeout"))
+	doneQuery(nil) // 重复调用无效
+	doneReq(context.Canceled)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 records, got:\n%s", buf.String())
+	}
+	records := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &records[i]); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+	}
+
+	reqStart, queryStart, queryEnd, reqEnd := records[0], records[1], records[2], records[3]
+	if reqStart["level"] != "Debug" || reqStart["msg"] != "http.request started" || reqStart["path"] != "/orders" ||
+		reqStart[OpPathKey] != "http.request" || reqStart[OpParentIDKey] != nil {
+		t.Fatalf("unexpected root start: %v", reqStart)
+	}
+	if queryStart[OpPathKey] != "http.request/db.query" || queryStart[OpParentIDKey] != reqStart[OpIDKey] ||
+		queryStart[OpIDKey] == reqStart[OpIDKey] {
+		t.Fatalf("child scope must reference parent: %v / %v", reqStart, queryStart)
+	}
+	if queryEnd["level"] != "Error" || queryEnd["status"] != OpStatusError || queryEnd["error"] != "timeout" ||
+		queryEnd["duration"] == nil || queryEnd[OpIDKey] != queryStart[OpIDKey] {
+		t.Fatalf("unexpected child end: %v", queryEnd)
+	}
+	if reqEnd["level"] != "Warn" || reqEnd["status"] != OpStatusCanceled || reqEnd["path"] != "/orders" {
+		t.Fatalf("unexpected root end: %v", reqEnd)
+	}
+}